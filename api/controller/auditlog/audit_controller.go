@@ -0,0 +1,73 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"user-management/api/responses"
+	"user-management/internal/audit"
+	"user-management/internal/validator"
+)
+
+type AuditController struct {
+	Store audit.Store
+}
+
+// ListEntries godoc
+// @Summary List audit log entries
+// @Description List recorded mutating API calls, oldest first, optionally filtered. Requires the admin token.
+// @Tags Audit
+// @Produce json
+// @Param actor query string false "Only calls made by this actor"
+// @Param userId query string false "Only calls targeting this user (UUID)"
+// @Param outcome query string false "Only calls with this outcome" Enums(success, failure)
+// @Param from query string false "Only calls after this RFC 3339 timestamp"
+// @Param to query string false "Only calls before this RFC 3339 timestamp"
+// @Param after query int false "Continue after the entry with this ID"
+// @Param limit query int false "Page size (1-500)" default(50)
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} EntryListResponse
+// @Failure 400 {object} responses.Problem "Invalid query parameters"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /audit [get]
+func (a *AuditController) ListEntries(w http.ResponseWriter, r *http.Request) {
+	listRequest, err := NewEntryListRequest(r.URL.Query())
+	if err == nil {
+		err = validator.Validate.Struct(listRequest)
+	}
+
+	if err != nil {
+		responses.WriteBadRequest(w, r, "invalid query parameters", err)
+		return
+	}
+
+	filter := listRequest.ToFilter()
+	// Fetch one extra entry to learn whether another page follows.
+	filter.Limit++
+
+	entries, err := a.Store.List(r.Context(), filter)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	response := EntryListResponse{Data: make([]EntryResponse, 0, len(entries))}
+
+	if len(entries) > listRequest.Limit {
+		entries = entries[:listRequest.Limit]
+		nextAfter := entries[len(entries)-1].ID
+		response.NextAfter = &nextAfter
+	}
+
+	for _, entry := range entries {
+		response.Data = append(response.Data, NewEntryResponse(entry))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,95 @@
+package auditlog
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"user-management/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+type EntryListRequest struct {
+	Actor   string     `json:"actor" validate:"omitempty,max=255"`
+	UserId  string     `json:"userId" validate:"omitempty,uuid"`
+	Outcome string     `json:"outcome" validate:"omitempty,oneof=success failure"`
+	From    *time.Time `json:"from"`
+	To      *time.Time `json:"to"`
+	After   int64      `json:"after" validate:"min=0"`
+	Limit   int        `json:"limit" validate:"min=1,max=500"`
+}
+
+// NewEntryListRequest reads the audit log filters from a query string,
+// applying the default page size where it is omitted.
+func NewEntryListRequest(values url.Values) (EntryListRequest, error) {
+	request := EntryListRequest{
+		Actor:   values.Get("actor"),
+		UserId:  values.Get("userId"),
+		Outcome: values.Get("outcome"),
+		Limit:   DefaultPageLimit,
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an integer", "limit")
+		}
+		request.Limit = limit
+	}
+
+	if raw := values.Get("after"); raw != "" {
+		after, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an integer", "after")
+		}
+		request.After = after
+	}
+
+	var err error
+
+	if request.From, err = optionalTimeParam(values, "from"); err != nil {
+		return request, err
+	}
+
+	if request.To, err = optionalTimeParam(values, "to"); err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+// ToFilter converts the request into a filter for the audit store. The user
+// ID must already have been validated.
+func (r EntryListRequest) ToFilter() audit.Filter {
+	target, _ := uuid.Parse(r.UserId)
+
+	return audit.Filter{
+		Actor:          r.Actor,
+		TargetUser:     target,
+		Outcome:        audit.Outcome(r.Outcome),
+		OccurredAfter:  r.From,
+		OccurredBefore: r.To,
+		AfterID:        r.After,
+		Limit:          r.Limit,
+	}
+}
+
+func optionalTimeParam(values url.Values, name string) (*time.Time, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %q must be an RFC 3339 timestamp", name)
+	}
+
+	return &value, nil
+}
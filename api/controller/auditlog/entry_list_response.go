@@ -0,0 +1,52 @@
+package auditlog
+
+import (
+	"time"
+	"user-management/internal/audit"
+
+	"github.com/google/uuid"
+)
+
+type EntryResponse struct {
+	ID         int64      `json:"id"`
+	OccurredAt time.Time  `json:"occurredAt"`
+	Actor      string     `json:"actor,omitempty"`
+	SourceIP   string     `json:"sourceIp"`
+	RequestID  string     `json:"requestId,omitempty"`
+	Method     string     `json:"method"`
+	Route      string     `json:"route"`
+	TargetUser *uuid.UUID `json:"targetUserId,omitempty"`
+	Status     int        `json:"status"`
+	Outcome    string     `json:"outcome" enums:"success,failure"`
+	PrevHash   string     `json:"prevHash"`
+	Hash       string     `json:"hash"`
+}
+
+// EntryListResponse is one page of the audit log, oldest first. NextAfter is
+// the after parameter that fetches the next page.
+type EntryListResponse struct {
+	Data      []EntryResponse `json:"data"`
+	NextAfter *int64          `json:"nextAfter,omitempty"`
+}
+
+func NewEntryResponse(entry audit.Entry) EntryResponse {
+	response := EntryResponse{
+		ID:         entry.ID,
+		OccurredAt: entry.OccurredAt,
+		Actor:      entry.Actor,
+		SourceIP:   entry.SourceIP,
+		RequestID:  entry.RequestID,
+		Method:     entry.Method,
+		Route:      entry.Route,
+		Status:     entry.Status,
+		Outcome:    string(entry.Outcome),
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+
+	if entry.TargetUser != uuid.Nil {
+		response.TargetUser = &entry.TargetUser
+	}
+
+	return response
+}
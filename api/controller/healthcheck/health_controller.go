@@ -0,0 +1,66 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"user-management/internal/health"
+)
+
+type HealthController struct {
+	Checker *health.Checker
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Report that the process is running. It does not check dependencies, so a failing database does not get the instance restarted.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} StatusResponse
+// @Router /healthz [get]
+func (h *HealthController) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, StatusResponse{Status: health.StatusUp})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Report whether the instance should receive traffic: the database answers, the schema matches this release and the instance is not shutting down. A degraded dependency still counts as ready.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} StatusResponse
+// @Failure 503 {object} StatusResponse "Not ready"
+// @Router /readyz [get]
+func (h *HealthController) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.Checker.Run(r.Context())
+
+	writeJSON(w, statusCode(report), StatusResponse{Status: report.Status})
+}
+
+// Health godoc
+// @Summary Detailed health
+// @Description Run every check and report each one's status, duration, error and details such as connection pool saturation, for operators.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} HealthResponse
+// @Failure 503 {object} HealthResponse "Not ready"
+// @Router /health [get]
+func (h *HealthController) Health(w http.ResponseWriter, r *http.Request) {
+	report := h.Checker.Run(r.Context())
+
+	writeJSON(w, statusCode(report), NewHealthResponse(report))
+}
+
+func statusCode(report health.Report) int {
+	if report.Ready() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	// Probes must see the current state, never a cached one.
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
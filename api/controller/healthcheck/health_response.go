@@ -0,0 +1,40 @@
+package healthcheck
+
+import "user-management/internal/health"
+
+type StatusResponse struct {
+	Status health.Status `json:"status" enums:"up,degraded,down" example:"up"`
+}
+
+type CheckResponse struct {
+	Status     health.Status  `json:"status" enums:"up,degraded,down" example:"up"`
+	Error      string         `json:"error,omitempty"`
+	DurationMs float64        `json:"durationMs" example:"1.2"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+type HealthResponse struct {
+	Status health.Status `json:"status" enums:"up,degraded,down" example:"up"`
+	// Draining is true once the instance has started shutting down.
+	Draining bool                     `json:"draining"`
+	Checks   map[string]CheckResponse `json:"checks"`
+}
+
+func NewHealthResponse(report health.Report) HealthResponse {
+	response := HealthResponse{
+		Status:   report.Status,
+		Draining: report.Draining,
+		Checks:   make(map[string]CheckResponse, len(report.Checks)),
+	}
+
+	for name, result := range report.Checks {
+		response.Checks[name] = CheckResponse{
+			Status:     result.Status,
+			Error:      result.Error,
+			DurationMs: float64(result.Duration.Microseconds()) / 1000,
+			Details:    result.Details,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,314 @@
+package subscription
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"user-management/api/responses"
+	"user-management/internal/validator"
+	"user-management/internal/webhook"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type SubscriptionController struct {
+	Store webhook.Store
+}
+
+// CreateSubscription godoc
+// @Summary Create webhook subscription
+// @Description Subscribe a URL to user events. Each delivery is POSTed with X-Webhook-Id, X-Webhook-Event, X-Webhook-Timestamp and an X-Webhook-Signature of "sha256=" followed by the hex HMAC-SHA256 of "timestamp.body" keyed with the secret. Requires the admin token.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body SubscriptionRequest true "Subscription"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 201 {object} SubscriptionResponse
+// @Failure 400 {object} responses.Problem "Validation failed"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks [post]
+func (s *SubscriptionController) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var subscriptionRequest SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&subscriptionRequest); err != nil {
+		responses.WriteBadRequest(w, r, "request body is not valid JSON", err)
+		return
+	}
+
+	if err := validator.Validate.Struct(subscriptionRequest); err != nil {
+		responses.WriteBadRequest(w, r, "one or more fields are invalid", err)
+		return
+	}
+
+	created, err := s.Store.CreateSubscription(r.Context(), webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        subscriptionRequest.URL,
+		EventTypes: subscriptionRequest.EventTypes,
+		Secret:     subscriptionRequest.Secret,
+	})
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/webhooks/"+created.ID.String())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	_ = json.NewEncoder(w).Encode(NewSubscriptionResponse(created))
+}
+
+// ListSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Description List every webhook subscription, oldest first. Requires the admin token.
+// @Tags Webhooks
+// @Produce json
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {array} SubscriptionResponse
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks [get]
+func (s *SubscriptionController) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := s.Store.ListSubscriptions(r.Context())
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]SubscriptionResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, NewSubscriptionResponse(subscription))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// GetSubscription godoc
+// @Summary Get webhook subscription
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 400 {object} responses.Problem "Invalid subscription ID"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 404 {object} responses.Problem "Subscription not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks/{id} [get]
+func (s *SubscriptionController) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := subscriptionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := s.Store.GetSubscription(r.Context(), subscriptionID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(NewSubscriptionResponse(subscription))
+}
+
+// DeleteSubscription godoc
+// @Summary Delete webhook subscription
+// @Description Stop sending events to the subscription and discard its deliveries. Requires the admin token.
+// @Tags Webhooks
+// @Param id path string true "Subscription ID (UUID)"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 204 "Subscription deleted"
+// @Failure 400 {object} responses.Problem "Invalid subscription ID"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 404 {object} responses.Problem "Subscription not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks/{id} [delete]
+func (s *SubscriptionController) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := subscriptionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.Store.DeleteSubscription(r.Context(), subscriptionID); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the events sent or waiting to be sent to a subscription, oldest first. Requires the admin token.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param status query string false "Only deliveries in this state" Enums(pending, delivered, dead)
+// @Param after query int false "Continue after the delivery with this ID"
+// @Param limit query int false "Page size (1-500)" default(50)
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {object} DeliveryListResponse
+// @Failure 400 {object} responses.Problem "Invalid subscription ID or query parameters"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks/{id}/deliveries [get]
+func (s *SubscriptionController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := subscriptionIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	listRequest, err := NewDeliveryListRequest(r.URL.Query())
+	if err == nil {
+		err = validator.Validate.Struct(listRequest)
+	}
+
+	if err != nil {
+		responses.WriteBadRequest(w, r, "invalid query parameters", err)
+		return
+	}
+
+	// Fetch one extra delivery to learn whether another page follows.
+	deliveries, err := s.Store.ListDeliveries(r.Context(), subscriptionID, webhook.DeliveryStatus(listRequest.Status), listRequest.After, listRequest.Limit+1)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	response := DeliveryListResponse{Data: make([]DeliveryResponse, 0, len(deliveries))}
+
+	if len(deliveries) > listRequest.Limit {
+		deliveries = deliveries[:listRequest.Limit]
+		nextAfter := deliveries[len(deliveries)-1].ID
+		response.NextAfter = &nextAfter
+	}
+
+	for _, delivery := range deliveries {
+		response.Data = append(response.Data, NewDeliveryResponse(delivery))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// ListAttempts godoc
+// @Summary List webhook delivery attempts
+// @Description List every attempt made to send a delivery, oldest first. Requires the admin token.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param deliveryId path int true "Delivery ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 200 {array} AttemptResponse
+// @Failure 400 {object} responses.Problem "Invalid subscription or delivery ID"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 404 {object} responses.Problem "Delivery not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks/{id}/deliveries/{deliveryId}/attempts [get]
+func (s *SubscriptionController) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, deliveryID, ok := deliveryParams(w, r)
+	if !ok {
+		return
+	}
+
+	attempts, err := s.Store.ListAttempts(r.Context(), subscriptionID, deliveryID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]AttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		response = append(response, NewAttemptResponse(attempt))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// ReplayDelivery godoc
+// @Summary Replay webhook delivery
+// @Description Send a delivery again with a fresh set of attempts, whether it was delivered, dead or still pending. Requires the admin token.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Subscription ID (UUID)"
+// @Param deliveryId path int true "Delivery ID"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 202 {object} DeliveryResponse "Delivery queued"
+// @Failure 400 {object} responses.Problem "Invalid subscription or delivery ID"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 404 {object} responses.Problem "Delivery not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (s *SubscriptionController) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, deliveryID, ok := deliveryParams(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.Store.Replay(r.Context(), subscriptionID, deliveryID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	_ = json.NewEncoder(w).Encode(NewDeliveryResponse(delivery))
+}
+
+func subscriptionIDParam(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	subscriptionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "subscription id must be a UUID", err)
+		return uuid.Nil, false
+	}
+	return subscriptionID, true
+}
+
+func deliveryParams(w http.ResponseWriter, r *http.Request) (uuid.UUID, int64, bool) {
+	subscriptionID, ok := subscriptionIDParam(w, r)
+	if !ok {
+		return uuid.Nil, 0, false
+	}
+
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryId"), 10, 64)
+	if err != nil {
+		responses.WriteBadRequest(w, r, "delivery id must be an integer", err)
+		return uuid.Nil, 0, false
+	}
+
+	return subscriptionID, deliveryID, true
+}
@@ -0,0 +1,48 @@
+package subscription
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+type SubscriptionRequest struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048" example:"https://example.com/hooks/users"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=user.created user.updated user.status_changed user.deleted user.restored" example:"user.created,user.deleted"`
+	// Secret keys the HMAC-SHA256 signature sent with every delivery. It is
+	// never returned.
+	Secret string `json:"secret" validate:"required,min=16,max=255"`
+}
+
+type DeliveryListRequest struct {
+	Status string `json:"status" validate:"omitempty,oneof=pending delivered dead"`
+	After  int64  `json:"after" validate:"min=0"`
+	Limit  int    `json:"limit" validate:"min=1,max=500"`
+}
+
+// NewDeliveryListRequest reads the delivery filters from a query string,
+// returning up to 50 deliveries unless another limit is given.
+func NewDeliveryListRequest(values url.Values) (DeliveryListRequest, error) {
+	request := DeliveryListRequest{
+		Status: values.Get("status"),
+		Limit:  50,
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an integer", "limit")
+		}
+		request.Limit = limit
+	}
+
+	if raw := values.Get("after"); raw != "" {
+		after, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an integer", "after")
+		}
+		request.After = after
+	}
+
+	return request, nil
+}
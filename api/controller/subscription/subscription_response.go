@@ -0,0 +1,81 @@
+package subscription
+
+import (
+	"time"
+	"user-management/internal/webhook"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type DeliveryResponse struct {
+	ID            int64      `json:"id"`
+	EventID       uuid.UUID  `json:"eventId"`
+	EventType     string     `json:"eventType"`
+	Status        string     `json:"status" enums:"pending,delivered,dead"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
+}
+
+// DeliveryListResponse is one page of deliveries, oldest first. NextAfter is
+// the after parameter that fetches the next page.
+type DeliveryListResponse struct {
+	Data      []DeliveryResponse `json:"data"`
+	NextAfter *int64             `json:"nextAfter,omitempty"`
+}
+
+type AttemptResponse struct {
+	AttemptedAt time.Time `json:"attemptedAt"`
+	StatusCode  int       `json:"statusCode,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	DurationMs  int64     `json:"durationMs"`
+}
+
+func NewSubscriptionResponse(s webhook.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+func NewDeliveryResponse(d webhook.Delivery) DeliveryResponse {
+	response := DeliveryResponse{
+		ID:        d.ID,
+		EventID:   d.EventID,
+		EventType: d.EventType,
+		Status:    string(d.Status),
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+		CreatedAt: d.CreatedAt,
+	}
+
+	if d.Status == webhook.DeliveryPending {
+		response.NextAttemptAt = &d.NextAttemptAt
+	}
+
+	if !d.DeliveredAt.IsZero() {
+		response.DeliveredAt = &d.DeliveredAt
+	}
+
+	return response
+}
+
+func NewAttemptResponse(a webhook.Attempt) AttemptResponse {
+	return AttemptResponse{
+		AttemptedAt: a.AttemptedAt,
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		DurationMs:  a.Duration.Milliseconds(),
+	}
+}
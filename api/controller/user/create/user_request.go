@@ -5,10 +5,10 @@ import (
 )
 
 type UserRequest struct {
-	FirstName string            `json:"firstName" validate:"required,min=2,max=50"`
-	LastName  string            `json:"lastName" validate:"required,min=2,max=50"`
-	Email     string            `json:"email" validate:"required,email"`
-	Phone     string            `json:"phone" validate:"required,e164"`
-	Age       int               `json:"age" validate:"required,gt=0"`
-	Status    domain.UserStatus `json:"status" validate:"omitempty,oneof=0 1"`
+	FirstName   string            `json:"firstName" validate:"required,min=2,max=50"`
+	LastName    string            `json:"lastName" validate:"required,min=2,max=50"`
+	Email       string            `json:"email" validate:"required,email"`
+	Phone       string            `json:"phone" validate:"required,e164"`
+	DateOfBirth string            `json:"dateOfBirth" validate:"required,datetime=2006-01-02,notfuture,minage=13" example:"1990-04-21"`
+	Status      domain.UserStatus `json:"status" validate:"omitempty,oneof=pending active" enums:"pending,active"`
 }
@@ -0,0 +1,60 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"user-management/internal/stream"
+)
+
+// eventStream writes server-sent events. Events are buffered until flush,
+// which reports the first write error; the stream is unusable after one.
+type eventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	err     error
+}
+
+// newEventStream sends the event stream headers, or reports false if w
+// cannot flush and so cannot stream.
+func newEventStream(w http.ResponseWriter) (*eventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Ask nginx and similar proxies not to buffer the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	return &eventStream{w: w, flusher: flusher}, true
+}
+
+func (s *eventStream) send(event stream.Event) {
+	s.printf("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+// reset tells the client that events were missed and it should reload what
+// it shows.
+func (s *eventStream) reset() {
+	s.printf("event: reset\ndata: {}\n\n")
+}
+
+func (s *eventStream) comment(text string) {
+	s.printf(": %s\n\n", text)
+}
+
+func (s *eventStream) flush() error {
+	if s.err == nil {
+		s.flusher.Flush()
+	}
+
+	return s.err
+}
+
+func (s *eventStream) printf(format string, args ...any) {
+	if s.err == nil {
+		_, s.err = fmt.Fprintf(s.w, format, args...)
+	}
+}
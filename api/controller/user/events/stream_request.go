@@ -0,0 +1,60 @@
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"user-management/internal/stream"
+
+	"github.com/google/uuid"
+)
+
+// StreamRequest narrows an event stream to some event types and one user.
+// Empty fields match everything.
+type StreamRequest struct {
+	Types  []string   `json:"type" validate:"dive,oneof=user.created user.updated user.status_changed user.deleted user.restored"`
+	UserId *uuid.UUID `json:"userId"`
+}
+
+// NewStreamRequest reads the type and userId query parameters. Types may be
+// repeated or comma-separated.
+func NewStreamRequest(values url.Values) (StreamRequest, error) {
+	var request StreamRequest
+
+	for _, raw := range values["type"] {
+		for _, eventType := range strings.Split(raw, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				request.Types = append(request.Types, eventType)
+			}
+		}
+	}
+
+	if raw := values.Get("userId"); raw != "" {
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be a UUID", "userId")
+		}
+		request.UserId = &userID
+	}
+
+	return request, nil
+}
+
+// Matches reports whether event passes the filters.
+func (r StreamRequest) Matches(event stream.Event) bool {
+	if r.UserId != nil && *r.UserId != event.UserID {
+		return false
+	}
+
+	if len(r.Types) == 0 {
+		return true
+	}
+
+	for _, eventType := range r.Types {
+		if eventType == event.Type {
+			return true
+		}
+	}
+
+	return false
+}
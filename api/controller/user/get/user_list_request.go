@@ -0,0 +1,179 @@
+package get
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"user-management/domain"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type UserListRequest struct {
+	Limit         int        `json:"limit" validate:"min=1,max=100"`
+	Offset        int        `json:"offset" validate:"min=0"`
+	Sort          string     `json:"sort" validate:"omitempty,oneof=firstName lastName email age dateOfBirth createdAt updatedAt"`
+	Order         string     `json:"order" validate:"omitempty,oneof=asc desc"`
+	Status        string     `json:"status" validate:"omitempty,oneof=pending active suspended locked deactivated"`
+	Email         string     `json:"email" validate:"omitempty,email"`
+	MinAge        *int       `json:"minAge" validate:"omitempty,gte=0"`
+	MaxAge        *int       `json:"maxAge" validate:"omitempty,gte=0"`
+	Name          string     `json:"name" validate:"omitempty,max=50"`
+	CreatedAfter  *time.Time `json:"createdAfter"`
+	CreatedBefore *time.Time `json:"createdBefore"`
+	UpdatedAfter  *time.Time `json:"updatedAfter"`
+	UpdatedBefore *time.Time `json:"updatedBefore"`
+	Cursor        string     `json:"cursor" validate:"omitempty,excluded_with=Offset"`
+}
+
+// NewUserListRequest reads the listing parameters from a query string,
+// applying the default page size where it is omitted.
+func NewUserListRequest(values url.Values) (UserListRequest, error) {
+	request := UserListRequest{
+		Limit:  DefaultPageLimit,
+		Sort:   values.Get("sort"),
+		Order:  values.Get("order"),
+		Email:  values.Get("email"),
+		Name:   values.Get("name"),
+		Status: values.Get("status"),
+		Cursor: values.Get("cursor"),
+	}
+
+	var err error
+
+	if request.Limit, err = intParam(values, "limit", request.Limit); err != nil {
+		return request, err
+	}
+
+	if request.Offset, err = intParam(values, "offset", 0); err != nil {
+		return request, err
+	}
+
+	if request.MinAge, err = optionalIntParam(values, "minAge"); err != nil {
+		return request, err
+	}
+
+	if request.MaxAge, err = optionalIntParam(values, "maxAge"); err != nil {
+		return request, err
+	}
+
+	// An inverted range could only ever match nobody.
+	if request.MinAge != nil && request.MaxAge != nil && *request.MinAge > *request.MaxAge {
+		return request, fmt.Errorf("query parameter %q must not be greater than %q", "minAge", "maxAge")
+	}
+
+	if request.CreatedAfter, err = optionalTimeParam(values, "createdAfter"); err != nil {
+		return request, err
+	}
+
+	if request.CreatedBefore, err = optionalTimeParam(values, "createdBefore"); err != nil {
+		return request, err
+	}
+
+	if request.UpdatedAfter, err = optionalTimeParam(values, "updatedAfter"); err != nil {
+		return request, err
+	}
+
+	if request.UpdatedBefore, err = optionalTimeParam(values, "updatedBefore"); err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+// ToQuery converts the request into a repository query, sorting by first name
+// in ascending order unless another order was requested.
+func (r UserListRequest) ToQuery() domain.UserQuery {
+	query := domain.UserQuery{
+		Filter: domain.UserFilter{
+			Email:         r.Email,
+			MinAge:        r.MinAge,
+			MaxAge:        r.MaxAge,
+			NamePrefix:    r.Name,
+			CreatedAfter:  r.CreatedAfter,
+			CreatedBefore: r.CreatedBefore,
+			UpdatedAfter:  r.UpdatedAfter,
+			UpdatedBefore: r.UpdatedBefore,
+		},
+		SortBy:   domain.UserSortField(r.Sort),
+		SortDesc: r.Order == "desc",
+		Limit:    r.Limit,
+		Offset:   r.Offset,
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = domain.UserSortByFirstName
+	}
+
+	if r.Status != "" {
+		status := domain.UserStatus(r.Status)
+		query.Filter.Status = &status
+	}
+
+	return query
+}
+
+// ToQueryAfter converts the request into a repository query that continues
+// from after. The sort order is taken from the cursor; requesting a different
+// one is an error because the position would be meaningless.
+func (r UserListRequest) ToQueryAfter(after domain.UserCursor) (domain.UserQuery, error) {
+	if r.Sort != "" && domain.UserSortField(r.Sort) != after.SortBy {
+		return domain.UserQuery{}, fmt.Errorf("cursor was issued for sort %q", after.SortBy)
+	}
+
+	if r.Order != "" && (r.Order == "desc") != after.SortDesc {
+		return domain.UserQuery{}, errors.New("cursor was issued for a different sort order")
+	}
+
+	query := r.ToQuery()
+	query.SortBy = after.SortBy
+	query.SortDesc = after.SortDesc
+
+	return query, nil
+}
+
+func intParam(values url.Values, name string, fallback int) (int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("query parameter %q must be an integer", name)
+	}
+
+	return value, nil
+}
+
+func optionalIntParam(values url.Values, name string) (*int, error) {
+	if values.Get(name) == "" {
+		return nil, nil
+	}
+
+	value, err := intParam(values, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+func optionalTimeParam(values url.Values, name string) (*time.Time, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("query parameter %q must be an RFC 3339 timestamp", name)
+	}
+
+	return &value, nil
+}
@@ -0,0 +1,76 @@
+package get
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// UserListResponse is one page of users. Total and Offset are only reported
+// for offset pages; NextCursor continues the listing with keyset paging.
+type UserListResponse struct {
+	Data       []UserResponseDto `json:"data"`
+	Total      *int64            `json:"total,omitempty"`
+	Limit      int               `json:"limit"`
+	Offset     *int              `json:"offset,omitempty"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+	Links      PageLinks         `json:"links"`
+}
+
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// NewPageLinks builds self/next/prev links for an offset page by rewriting
+// the offset of the request URL, keeping every other query parameter as is.
+func NewPageLinks(requestURL *url.URL, limit, offset int, total int64) PageLinks {
+	links := PageLinks{
+		Self: pageURL(requestURL, limit, offset),
+	}
+
+	if int64(offset+limit) < total {
+		links.Next = pageURL(requestURL, limit, offset+limit)
+	}
+
+	if offset > 0 {
+		links.Prev = pageURL(requestURL, limit, max(offset-limit, 0))
+	}
+
+	return links
+}
+
+// NewCursorLinks builds self/next links for a keyset page. There is no prev
+// link because continuation tokens only move forward.
+func NewCursorLinks(requestURL *url.URL, nextCursor string) PageLinks {
+	links := PageLinks{
+		Self: requestURL.RequestURI(),
+	}
+
+	if nextCursor != "" {
+		query := requestURL.Query()
+		query.Del("offset")
+		query.Set("cursor", nextCursor)
+
+		link := url.URL{
+			Path:     requestURL.Path,
+			RawQuery: query.Encode(),
+		}
+		links.Next = link.String()
+	}
+
+	return links
+}
+
+func pageURL(requestURL *url.URL, limit, offset int) string {
+	query := requestURL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	link := url.URL{
+		Path:     requestURL.Path,
+		RawQuery: query.Encode(),
+	}
+
+	return link.String()
+}
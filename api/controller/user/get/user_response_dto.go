@@ -1,17 +1,41 @@
 package get
 
 import (
+	"time"
 	"user-management/domain"
 
 	"github.com/google/uuid"
 )
 
 type UserResponseDto struct {
-	UserId    uuid.UUID         `json:"userId" validate:"required"`
-	FirstName string            `json:"firstName" validate:"required,min=2,max=50"`
-	LastName  string            `json:"lastName" validate:"required,min=2,max=50"`
-	Email     string            `json:"email" validate:"required,email"`
-	Phone     string            `json:"phone" validate:"required,e164"`
-	Age       int               `json:"age" validate:"required,gt=0"`
-	Status    domain.UserStatus `json:"status" validate:"omitempty,oneof=0 1"`
+	UserId      uuid.UUID `json:"userId" validate:"required"`
+	FirstName   string    `json:"firstName" validate:"required,min=2,max=50"`
+	LastName    string    `json:"lastName" validate:"required,min=2,max=50"`
+	Email       string    `json:"email" validate:"required,email"`
+	Phone       string    `json:"phone" validate:"required,e164"`
+	DateOfBirth string    `json:"dateOfBirth" example:"1990-04-21"`
+	// Age is derived from DateOfBirth and kept for older clients.
+	Age       int               `json:"age"`
+	Status    domain.UserStatus `json:"status" enums:"pending,active,suspended,locked,deactivated"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	CreatedBy string            `json:"createdBy,omitempty"`
+	UpdatedBy string            `json:"updatedBy,omitempty"`
+}
+
+func NewUserResponseDto(u domain.User) UserResponseDto {
+	return UserResponseDto{
+		UserId:      u.UserId,
+		FirstName:   u.FirstName,
+		LastName:    u.LastName,
+		Email:       u.Email,
+		Phone:       u.Phone,
+		DateOfBirth: u.DateOfBirth.Format(time.DateOnly),
+		Age:         u.Age,
+		Status:      u.Status,
+		CreatedAt:   u.CreatedAt,
+		UpdatedAt:   u.UpdatedAt,
+		CreatedBy:   u.CreatedBy,
+		UpdatedBy:   u.UpdatedBy,
+	}
 }
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"time"
+	"user-management/domain"
+)
+
+type RevisionResponse struct {
+	Revision  int                `json:"revision"`
+	Operation string             `json:"operation" enums:"created,updated,deleted,restored"`
+	Changes   domain.UserChanges `json:"changes"`
+	ChangedBy string             `json:"changedBy,omitempty"`
+	ChangedAt time.Time          `json:"changedAt"`
+}
+
+type UserHistoryResponse struct {
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+func NewUserHistoryResponse(revisions []domain.UserRevision) UserHistoryResponse {
+	response := UserHistoryResponse{Revisions: make([]RevisionResponse, 0, len(revisions))}
+
+	for _, revision := range revisions {
+		response.Revisions = append(response.Revisions, RevisionResponse{
+			Revision:  revision.Revision,
+			Operation: string(revision.Operation),
+			Changes:   revision.Changes,
+			ChangedBy: revision.ChangedBy,
+			ChangedAt: revision.ChangedAt,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,45 @@
+package history
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"user-management/api/controller/user/get"
+)
+
+// SnapshotRequest picks the point in a user's history to look at, either by
+// revision number or by time.
+type SnapshotRequest struct {
+	Revision *int       `json:"revision" validate:"omitempty,min=1,required_without=At,excluded_with=At"`
+	At       *time.Time `json:"at" validate:"required_without=Revision"`
+}
+
+// NewSnapshotRequest reads the revision and at query parameters.
+func NewSnapshotRequest(values url.Values) (SnapshotRequest, error) {
+	var request SnapshotRequest
+
+	if raw := values.Get("revision"); raw != "" {
+		revision, err := strconv.Atoi(raw)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an integer", "revision")
+		}
+		request.Revision = &revision
+	}
+
+	if raw := values.Get("at"); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return request, fmt.Errorf("query parameter %q must be an RFC 3339 timestamp", "at")
+		}
+		request.At = &at
+	}
+
+	return request, nil
+}
+
+// SnapshotResponse is a user as it was at some revision.
+type SnapshotResponse struct {
+	Revision int `json:"revision"`
+	get.UserResponseDto
+}
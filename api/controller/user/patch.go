@@ -0,0 +1,88 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"user-management/api/controller/user/patch"
+	"user-management/api/responses"
+	"user-management/domain"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// acceptPatch lists the patch formats PATCH /users/{id} understands.
+const acceptPatch = patch.MergePatchContentType + ", " + patch.JSONPatchContentType
+
+// applyPatch applies the request body to user as either a JSON Merge Patch
+// (RFC 7396) or a JSON Patch (RFC 6902), depending on Content-Type, and
+// decodes the result. ok is false once a problem response has been written.
+func applyPatch(w http.ResponseWriter, r *http.Request, user domain.User) (document patch.UserDocument, ok bool) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != patch.MergePatchContentType && mediaType != patch.JSONPatchContentType {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		responses.WriteProblem(w, r, responses.Problem{
+			Type:   responses.ProblemTypeUnsupportedMediaType,
+			Status: http.StatusUnsupportedMediaType,
+			Detail: "Content-Type must be one of " + acceptPatch,
+		})
+		return document, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		responses.WriteBadRequest(w, r, "request body could not be read", err)
+		return document, false
+	}
+
+	original, err := json.Marshal(patch.NewUserDocument(user))
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return document, false
+	}
+
+	var patched []byte
+	if mediaType == patch.MergePatchContentType {
+		patched, err = jsonpatch.MergePatch(original, body)
+		if err != nil {
+			responses.WriteBadRequest(w, r, "merge patch is not a valid JSON document", nil)
+			return document, false
+		}
+	} else {
+		operations, decodeErr := jsonpatch.DecodePatch(body)
+		if decodeErr != nil {
+			responses.WriteBadRequest(w, r, "JSON patch is not a valid list of operations", decodeErr)
+			return document, false
+		}
+
+		patched, err = operations.Apply(original)
+		switch {
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			responses.WriteProblem(w, r, responses.Problem{
+				Type:   responses.ProblemTypeConflict,
+				Status: http.StatusConflict,
+				Detail: "a test operation did not match the current user",
+			})
+			return document, false
+		case err != nil:
+			responses.WriteProblem(w, r, responses.Problem{
+				Type:   responses.ProblemTypeValidation,
+				Status: http.StatusUnprocessableEntity,
+				Detail: "JSON patch cannot be applied to this user: " + err.Error(),
+			})
+			return document, false
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&document); err != nil {
+		responses.WriteBadRequest(w, r, "patched user is not valid", err)
+		return document, false
+	}
+
+	return document, true
+}
@@ -0,0 +1,56 @@
+package patch
+
+import (
+	"time"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+)
+
+// Media types accepted by PATCH /users/{id}.
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// UserDocument is the JSON representation of a user that patches are applied
+// to. Every field is present, so a patch can clear a field or set it to its
+// zero value, and the patched document is validated as a whole. Status is
+// included so that test operations can check it, but a patch may not change it.
+type UserDocument struct {
+	FirstName   string            `json:"firstName" validate:"required,min=2,max=50"`
+	LastName    string            `json:"lastName" validate:"required,min=2,max=50"`
+	Email       string            `json:"email" validate:"required,email"`
+	Phone       string            `json:"phone" validate:"required,e164"`
+	DateOfBirth string            `json:"dateOfBirth" validate:"required,datetime=2006-01-02,notfuture,minage=13" example:"1990-04-21"`
+	Status      domain.UserStatus `json:"status" validate:"required,oneof=pending active suspended locked deactivated" enums:"pending,active,suspended,locked,deactivated"`
+}
+
+func NewUserDocument(user domain.User) UserDocument {
+	return UserDocument{
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Email:       user.Email,
+		Phone:       user.Phone,
+		DateOfBirth: user.DateOfBirth.Format(time.DateOnly),
+		Status:      user.Status,
+	}
+}
+
+// ToUser returns the patched user, carrying the version it was read at so
+// the write only succeeds if nobody changed it in between. The document must
+// have been validated.
+func (d UserDocument) ToUser(id uuid.UUID, version int) domain.User {
+	dateOfBirth, _ := time.Parse(time.DateOnly, d.DateOfBirth)
+
+	return domain.User{
+		UserId:      id,
+		FirstName:   d.FirstName,
+		LastName:    d.LastName,
+		Email:       d.Email,
+		Phone:       d.Phone,
+		DateOfBirth: dateOfBirth,
+		Status:      d.Status,
+		Version:     version,
+	}
+}
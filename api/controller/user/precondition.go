@@ -0,0 +1,92 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-management/api/responses"
+)
+
+// etag renders a user version as a strong entity tag.
+func etag(version int) string {
+	return fmt.Sprintf("%q", strconv.Itoa(version))
+}
+
+// noneMatch reports whether an If-None-Match header value matches tag, so a
+// conditional GET should be answered with 304 Not Modified. The header is
+// either "*", which matches any current representation, or a comma-separated
+// list of entity tags compared weakly, ignoring any W/ prefix (RFC 9110
+// section 13.1.2).
+func noneMatch(header string, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+
+	tag = strings.TrimPrefix(tag, "W/")
+
+	for header != "" {
+		header = strings.TrimLeft(header, " \t,")
+		weak := strings.TrimPrefix(header, "W/")
+		if !strings.HasPrefix(weak, `"`) {
+			// Not an entity tag; skip to the next list member.
+			_, header, _ = strings.Cut(header, ",")
+			continue
+		}
+
+		// Opaque tags may contain commas, so find the closing quote rather
+		// than splitting the list on them.
+		end := strings.IndexByte(weak[1:], '"')
+		if end < 0 {
+			return false
+		}
+		if weak[:end+2] == tag {
+			return true
+		}
+		header = weak[end+2:]
+	}
+
+	return false
+}
+
+// expectedVersion reads the version a write is conditional on from If-Match.
+// It returns 0 when the write is unconditional, either because the header is
+// absent and not required or because it is "*". ok is false once a problem
+// response has been written.
+func (u *UserController) expectedVersion(w http.ResponseWriter, r *http.Request) (version int, ok bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	if ifMatch == "" {
+		if u.Env != nil && u.Env.RequireIfMatch {
+			responses.WriteProblem(w, r, responses.Problem{
+				Type:   responses.ProblemTypePreconditionRequired,
+				Status: http.StatusPreconditionRequired,
+				Detail: "this request must be made conditional with an If-Match header",
+			})
+			return 0, false
+		}
+		return 0, true
+	}
+
+	if ifMatch == "*" {
+		return 0, true
+	}
+
+	// Only a single strong ETag can be matched by the conditional write.
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err == nil {
+		version, err = strconv.Atoi(unquoted)
+	}
+
+	if err != nil || version <= 0 {
+		responses.WriteProblem(w, r, responses.Problem{
+			Type:   responses.ProblemTypePreconditionFailed,
+			Status: http.StatusPreconditionFailed,
+			Detail: "If-Match does not name a current version of this user",
+		})
+		return 0, false
+	}
+
+	return version, true
+}
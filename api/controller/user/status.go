@@ -0,0 +1,70 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"user-management/api/controller/user/create"
+	"user-management/api/controller/user/status"
+	"user-management/api/responses"
+	"user-management/domain"
+	"user-management/internal/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// changeStatus moves the user named in the URL to next, recording the reason
+// given in the body. Transitions the lifecycle forbids are reported as 409.
+func (u *UserController) changeStatus(w http.ResponseWriter, r *http.Request, next domain.UserStatus) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	var changeRequest status.ChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&changeRequest); err != nil {
+		responses.WriteBadRequest(w, r, "request body is not valid JSON", err)
+		return
+	}
+
+	if err := validator.Validate.Struct(changeRequest); err != nil {
+		responses.WriteBadRequest(w, r, "one or more fields are invalid", err)
+		return
+	}
+
+	version, ok := u.expectedVersion(w, r)
+	if !ok {
+		return
+	}
+
+	changedUser, err := u.ChangeStatus(r.Context(), userID, next, changeRequest.Reason, version)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(int(changedUser.Version)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(create.UserResponse{
+		UserID:    changedUser.UserID,
+		Email:     changedUser.Email,
+		Status:    changedUser.Status,
+		CreatedAt: changedUser.CreatedAt,
+		UpdatedAt: changedUser.UpdatedAt,
+		CreatedBy: changedUser.CreatedBy,
+		UpdatedBy: changedUser.UpdatedBy,
+	})
+}
+
+// writeStatusNotEditable rejects a PUT or PATCH that sets the status, which
+// would skip the reason the status endpoints record.
+func writeStatusNotEditable(w http.ResponseWriter, r *http.Request) {
+	responses.WriteProblem(w, r, responses.Problem{
+		Type:   responses.ProblemTypeValidation,
+		Status: http.StatusUnprocessableEntity,
+		Detail: "status cannot be changed here; use the status endpoints",
+	})
+}
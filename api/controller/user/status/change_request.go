@@ -0,0 +1,6 @@
+package status
+
+// ChangeRequest is the body of the activate, suspend and deactivate actions.
+type ChangeRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -1,10 +1,14 @@
 package update
 
+import "encoding/json"
+
 type UserRequest struct {
-	FirstName string `json:"firstName,omitempty" validate:"omitempty,min=2,max=50"`
-	LastName  string `json:"lastName,omitempty" validate:"omitempty,min=2,max=50"`
-	Email     string `json:"email,omitempty" validate:"omitempty,email"`
-	Phone     string `json:"phone,omitempty" validate:"omitempty,e164"`
-	Age       int    `json:"age,omitempty" validate:"omitempty,gte=0,lte=150"`
-	Status    int    `json:"status,omitempty"`
+	FirstName   string `json:"firstName,omitempty" validate:"omitempty,min=2,max=50"`
+	LastName    string `json:"lastName,omitempty" validate:"omitempty,min=2,max=50"`
+	Email       string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone       string `json:"phone,omitempty" validate:"omitempty,e164"`
+	DateOfBirth string `json:"dateOfBirth,omitempty" validate:"omitempty,datetime=2006-01-02,notfuture,minage=13" example:"1990-04-21"`
+	// Status is only read to be rejected: status changes go through the
+	// status endpoints so that they record a reason.
+	Status json.RawMessage `json:"status,omitempty" swaggerignore:"true"`
 }
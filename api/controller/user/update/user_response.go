@@ -3,7 +3,11 @@ package update
 import "github.com/jackc/pgx/v5/pgtype"
 
 type UserResponse struct {
-	UserID pgtype.UUID
-	Email  string
-	Status int32
+	UserID    pgtype.UUID
+	Email     string
+	Status    string
+	CreatedAt pgtype.Timestamptz `swaggertype:"string" format:"date-time"`
+	UpdatedAt pgtype.Timestamptz `swaggertype:"string" format:"date-time"`
+	CreatedBy pgtype.Text        `swaggertype:"string"`
+	UpdatedBy pgtype.Text        `swaggertype:"string"`
 }
@@ -2,12 +2,19 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 	"user-management/api/controller/user/create"
+	"user-management/api/controller/user/events"
+	"user-management/api/controller/user/get"
+	"user-management/api/controller/user/history"
 	"user-management/api/controller/user/update"
 	"user-management/api/responses"
 	"user-management/bootstrap"
 	"user-management/domain"
+	"user-management/internal/cursor"
+	"user-management/internal/stream"
 	"user-management/internal/validator"
 
 	"github.com/go-chi/chi/v5"
@@ -16,7 +23,12 @@ import (
 
 type UserController struct {
 	domain.UserRepository
-	Env *bootstrap.Env
+	Env     *bootstrap.Env
+	Cursors *cursor.Codec
+	// Events feeds the event stream; Heartbeat is how often an idle stream
+	// sends a comment to keep proxies from closing it, zero for never.
+	Events    *stream.Broker
+	Heartbeat time.Duration
 }
 
 // CreateUser godoc
@@ -26,56 +38,69 @@ type UserController struct {
 // @Accept json
 // @Produce json
 // @Param user body create.UserRequest true "User data"
-// @Success 201 {object} domain.User
-// @Failure 400 {object} responses.Response "Validation failed"
-// @Failure 404 {object} responses.Response "User not found"
-// @Failure 500 {object} responses.Response "Internal Server Error"
+// @Param Accept-Language header string false "Language for validation messages (en, es)"
+// @Param Idempotency-Key header string false "Makes the request safe to retry; the first response is replayed for the same key"
+// @Param X-Actor header string false "Who is making the change; recorded as createdBy/updatedBy"
+// @Success 201 {object} create.UserResponse
+// @Header 201 {string} Location "URL of the new user"
+// @Failure 400 {object} responses.Problem "Validation failed"
+// @Failure 409 {object} responses.Problem "Email already in use, or a request with the same Idempotency-Key is in progress"
+// @Failure 413 {object} responses.Problem "Body sent with an Idempotency-Key is too large"
+// @Failure 422 {object} responses.Problem "User violates a data constraint, or the Idempotency-Key was used for a different request"
+// @Failure 500 {object} responses.Problem "Internal Server Error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
 // @Router /users [post]
 func (u *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var createUserRequest create.UserRequest
 
-	_ = json.NewDecoder(r.Body).Decode(&createUserRequest)
+	err := json.NewDecoder(r.Body).Decode(&createUserRequest)
+	if err != nil {
+		responses.WriteBadRequest(w, r, "request body is not valid JSON", err)
+		return
+	}
 
 	valError := validator.Validate.Struct(createUserRequest)
 	if valError != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "validation failed",
-			Errors:  valError.Error(),
-		})
+		responses.WriteBadRequest(w, r, "one or more fields are invalid", valError)
 		return
 	}
 
-	if createUserRequest.Status == domain.UserStatusDefault {
+	if createUserRequest.Status == "" {
 		createUserRequest.Status = domain.UserStatusActive
 	}
 
+	// The format was checked by the datetime validation above.
+	dateOfBirth, _ := time.Parse(time.DateOnly, createUserRequest.DateOfBirth)
+
 	user := domain.User{
-		UserId:    uuid.New(),
-		FirstName: createUserRequest.FirstName,
-		LastName:  createUserRequest.LastName,
-		Email:     createUserRequest.Email,
-		Phone:     createUserRequest.Phone,
-		Age:       createUserRequest.Age,
-		Status:    createUserRequest.Status,
+		UserId:      uuid.New(),
+		FirstName:   createUserRequest.FirstName,
+		LastName:    createUserRequest.LastName,
+		Email:       createUserRequest.Email,
+		Phone:       createUserRequest.Phone,
+		DateOfBirth: dateOfBirth,
+		Status:      createUserRequest.Status,
 	}
 
 	createdUser, err2 := u.Create(r.Context(), &user)
 
 	createUserResponse := create.UserResponse{
-		UserID: createdUser.UserID,
-		Email:  createdUser.Email,
-		Status: createdUser.Status,
+		UserID:    createdUser.UserID,
+		Email:     createdUser.Email,
+		Status:    createdUser.Status,
+		CreatedAt: createdUser.CreatedAt,
+		UpdatedAt: createdUser.UpdatedAt,
+		CreatedBy: createdUser.CreatedBy,
+		UpdatedBy: createdUser.UpdatedBy,
 	}
 	if err2 != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "Internal Server Error",
-			Errors:  err2.Error(),
-		})
+		responses.WriteError(w, r, err2)
 		return
 	}
 
+	w.Header().Set("ETag", etag(int(createdUser.Version)))
+	w.Header().Set("Location", "/users/"+user.UserId.String())
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 
@@ -84,46 +109,105 @@ func (u *UserController) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 // GetAllUsers godoc
 // @Summary Get all users
-// @Description Retrieve all users
+// @Description Retrieve a page of users, optionally filtered and sorted. Pages are addressed either by offset or by the opaque nextCursor token.
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Success 200 {array} domain.User "List of users"
-// @Failure 500 {object} responses.Response "Internal Server Error"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Param offset query int false "Number of users to skip" default(0)
+// @Param sort query string false "Sort field" Enums(firstName, lastName, email, age, dateOfBirth, createdAt, updatedAt) default(firstName)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(asc)
+// @Param status query string false "Filter by status" Enums(pending, active, suspended, locked, deactivated)
+// @Param email query string false "Filter by email (case-insensitive)"
+// @Param minAge query int false "Minimum age"
+// @Param maxAge query int false "Maximum age"
+// @Param name query string false "First or last name prefix"
+// @Param createdAfter query string false "Only users created after this RFC 3339 time"
+// @Param createdBefore query string false "Only users created before this RFC 3339 time"
+// @Param updatedAfter query string false "Only users last changed after this RFC 3339 time"
+// @Param updatedBefore query string false "Only users last changed before this RFC 3339 time"
+// @Param cursor query string false "Continuation token from a previous page's nextCursor; replaces offset"
+// @Success 200 {object} get.UserListResponse "Page of users"
+// @Failure 400 {object} responses.Problem "Invalid query parameters"
+// @Failure 500 {object} responses.Problem "Internal Server Error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
 // @Router /users [get]
 func (u *UserController) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	userEntities, err2 := u.GetAll(r.Context())
+	listRequest, err := get.NewUserListRequest(r.URL.Query())
+	if err == nil {
+		err = validator.Validate.Struct(listRequest)
+	}
 
-	usersDtoResponse := make([]domain.User, 0, len(userEntities))
+	query := listRequest.ToQuery()
 
-	for _, u := range userEntities {
-		usersDtoResponse = append(usersDtoResponse, domain.User{
-			UserId:    u.UserId,
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Email:     u.Email,
-			Phone:     u.Phone,
-			Age:       int(u.Age),
-			Status:    u.Status,
-		})
+	var after domain.UserCursor
+	if err == nil && listRequest.Cursor != "" {
+		after, err = u.Cursors.Decode(listRequest.Cursor)
+		if err == nil {
+			query, err = listRequest.ToQueryAfter(after)
+		}
+	}
+
+	if err != nil {
+		responses.WriteBadRequest(w, r, "invalid query parameters", err)
+		return
+	}
+
+	var userEntities []domain.User
+	var total int64
+	var err2 error
+
+	if listRequest.Cursor != "" {
+		// Fetch one extra row to learn whether another page follows.
+		query.Limit++
+		userEntities, err2 = u.GetAllAfter(r.Context(), query, after)
+		query.Limit--
+	} else {
+		userEntities, total, err2 = u.GetAll(r.Context(), query)
 	}
+
 	if err2 != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		err := json.NewEncoder(w).Encode(responses.Response{
-			Message: "Internal Server Error",
-			Errors:  err2.Error(),
-		})
-		if err != nil {
-			return
-		}
+		responses.WriteError(w, r, err2)
 		return
 	}
 
+	var nextCursor string
+	hasMore := int64(query.Offset+query.Limit) < total
+	if listRequest.Cursor != "" {
+		hasMore = len(userEntities) > query.Limit
+		userEntities = userEntities[:min(len(userEntities), query.Limit)]
+	}
+
+	if hasMore && len(userEntities) > 0 {
+		last := userEntities[len(userEntities)-1]
+		nextCursor = u.Cursors.Encode(domain.NewUserCursor(last, query.SortBy, query.SortDesc))
+	}
+
+	usersDtoResponse := make([]get.UserResponseDto, 0, len(userEntities))
+
+	for _, u := range userEntities {
+		usersDtoResponse = append(usersDtoResponse, get.NewUserResponseDto(u))
+	}
+
+	listResponse := get.UserListResponse{
+		Data:       usersDtoResponse,
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	}
+
+	if listRequest.Cursor != "" {
+		listResponse.Links = get.NewCursorLinks(r.URL, nextCursor)
+	} else {
+		listResponse.Total = &total
+		listResponse.Offset = &query.Offset
+		listResponse.Links = get.NewPageLinks(r.URL, query.Limit, query.Offset, total)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	_ = json.NewEncoder(w).Encode(usersDtoResponse)
+	_ = json.NewEncoder(w).Encode(listResponse)
 }
 
 // GetUserById godoc
@@ -133,66 +217,66 @@ func (u *UserController) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
-// @Success 200 {object} domain.User "User found"
-// @Failure 400 {object} responses.Response "Invalid user ID"
-// @Failure 404 {object} responses.Response "User not found"
-// @Failure 500 {object} responses.Response "Internal server error"
+// @Param If-None-Match header string false "ETags from previous reads, comma-separated, or *"
+// @Success 200 {object} get.UserResponseDto "User found"
+// @Header 200 {string} ETag "Current version of the user"
+// @Success 304 "User has not changed"
+// @Failure 400 {object} responses.Problem "Invalid user ID"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
 // @Router /users/{id} [get]
 func (u *UserController) GetUserById(w http.ResponseWriter, r *http.Request) {
 	idParam := chi.URLParam(r, "id")
 
 	userID, err := uuid.Parse(idParam)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(responses.Response{
-			Message: "Internal Server Error",
-			Errors:  err.Error(),
-		})
-
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
 		return
 	}
 
 	userEntity, err2 := u.GetById(r.Context(), userID)
-
-	userResponse := domain.User{
-		UserId:    userEntity.UserId,
-		FirstName: userEntity.FirstName,
-		LastName:  userEntity.LastName,
-		Email:     userEntity.Email,
-		Phone:     userEntity.Phone,
-		Age:       userEntity.Age,
-		Status:    userEntity.Status,
-	}
 	if err2 != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(responses.Response{
-			Message: "user not found",
-			Errors:  err2.Error(),
-		})
+		responses.WriteError(w, r, err2)
+		return
+	}
+
+	w.Header().Set("ETag", etag(userEntity.Version))
 
+	if noneMatch(r.Header.Get("If-None-Match"), etag(userEntity.Version)) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	_ = json.NewEncoder(w).Encode(userResponse)
+	_ = json.NewEncoder(w).Encode(get.NewUserResponseDto(userEntity))
 }
 
 // UpdateUser godoc
 // @Summary Update user
-// @Description Update an existing user by ID
+// @Description Update an existing user by ID. The status cannot be set here; use the activate, suspend and deactivate endpoints, which record a reason.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
 // @Param user body update.UserRequest true "Update user payload"
+// @Param Accept-Language header string false "Language for validation messages (en, es)"
+// @Param If-Match header string false "ETag the update is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as createdBy/updatedBy"
 // @Success 200 {object} create.UserResponse "User updated successfully"
-// @Failure 400 {object} responses.Response "Invalid request / Validation failed"
-// @Failure 404 {object} responses.Response
-// @Failure 500 {object} responses.Response "Internal server error"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid request / Validation failed"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 409 {object} responses.Problem "Email already in use"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 422 {object} responses.Problem "Status given, or user violates a data constraint"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
 // @Router /users/{id} [put]
 func (u *UserController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	var updateUserRequest update.UserRequest
@@ -201,77 +285,169 @@ func (u *UserController) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	userID, errId := uuid.Parse(idParam)
 
 	if errId != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "user not found",
-			Errors:  errId.Error(),
-		})
+		responses.WriteBadRequest(w, r, "user id must be a UUID", errId)
 		return
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&updateUserRequest)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "Json Conversion Issue",
-			Errors:  err.Error(),
-		})
+		responses.WriteBadRequest(w, r, "request body is not valid JSON", err)
 		return
 	}
 
 	valError := validator.Validate.Struct(updateUserRequest)
 	if valError != nil {
-		http.Error(w, valError.Error(), http.StatusBadRequest)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "validation failed",
-			Errors:  valError.Error(),
-		})
+		responses.WriteBadRequest(w, r, "one or more fields are invalid", valError)
+		return
+	}
+
+	if updateUserRequest.Status != nil {
+		writeStatusNotEditable(w, r)
 		return
 	}
 
+	version, ok := u.expectedVersion(w, r)
+	if !ok {
+		return
+	}
+
+	// Left as the zero time, and so unchanged, when the request omits it.
+	dateOfBirth, _ := time.Parse(time.DateOnly, updateUserRequest.DateOfBirth)
+
 	user := domain.User{
-		FirstName: updateUserRequest.FirstName,
-		LastName:  updateUserRequest.LastName,
-		Email:     updateUserRequest.Email,
-		Phone:     updateUserRequest.Phone,
-		Age:       updateUserRequest.Age,
-		Status:    domain.UserStatus(updateUserRequest.Status),
+		FirstName:   updateUserRequest.FirstName,
+		LastName:    updateUserRequest.LastName,
+		Email:       updateUserRequest.Email,
+		Phone:       updateUserRequest.Phone,
+		DateOfBirth: dateOfBirth,
+		Version:     version,
 	}
 
 	updatedUser, err2 := u.Update(r.Context(), userID, &user)
 
 	createUserResponse := create.UserResponse{
-		UserID: updatedUser.UserID,
-		Email:  updatedUser.Email,
-		Status: updatedUser.Status,
+		UserID:    updatedUser.UserID,
+		Email:     updatedUser.Email,
+		Status:    updatedUser.Status,
+		CreatedAt: updatedUser.CreatedAt,
+		UpdatedAt: updatedUser.UpdatedAt,
+		CreatedBy: updatedUser.CreatedBy,
+		UpdatedBy: updatedUser.UpdatedBy,
 	}
 	if err2 != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "user not found",
-			Errors:  err2.Error(),
-		})
+		responses.WriteError(w, r, err2)
 		return
 	}
 
+	w.Header().Set("ETag", etag(int(updatedUser.Version)))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
 	_ = json.NewEncoder(w).Encode(createUserResponse)
 }
 
+// PatchUser godoc
+// @Summary Patch user
+// @Description Partially update a user with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902). The patched user is validated as a whole and written atomically. The status cannot be changed here; use the activate, suspend and deactivate endpoints, which record a reason.
+// @Tags Users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param patch body patch.UserDocument true "Merge patch, or a list of JSON Patch operations"
+// @Param Accept-Language header string false "Language for validation messages (en, es)"
+// @Param If-Match header string false "ETag the patch is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as createdBy/updatedBy"
+// @Success 200 {object} create.UserResponse "User patched successfully"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid patch / Validation failed"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 409 {object} responses.Problem "Email already in use, or a test operation failed"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 415 {object} responses.Problem "Unsupported patch format"
+// @Failure 422 {object} responses.Problem "Patch cannot be applied to this user, or changes the status"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id} [patch]
+func (u *UserController) PatchUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	version, ok := u.expectedVersion(w, r)
+	if !ok {
+		return
+	}
+
+	current, err := u.GetById(r.Context(), userID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	if version != 0 && version != current.Version {
+		responses.WriteError(w, r, domain.NewPreconditionFailedError("user has been modified since it was read", nil))
+		return
+	}
+
+	document, ok := applyPatch(w, r, current)
+	if !ok {
+		return
+	}
+
+	if err := validator.Validate.Struct(document); err != nil {
+		responses.WriteBadRequest(w, r, "one or more fields are invalid", err)
+		return
+	}
+
+	if document.Status != current.Status {
+		writeStatusNotEditable(w, r)
+		return
+	}
+
+	patched := document.ToUser(userID, current.Version)
+
+	updatedUser, err := u.Replace(r.Context(), &patched)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(int(updatedUser.Version)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(create.UserResponse{
+		UserID:    updatedUser.UserID,
+		Email:     updatedUser.Email,
+		Status:    updatedUser.Status,
+		CreatedAt: updatedUser.CreatedAt,
+		UpdatedAt: updatedUser.UpdatedAt,
+		CreatedBy: updatedUser.CreatedBy,
+		UpdatedBy: updatedUser.UpdatedBy,
+	})
+}
+
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Delete a user by ID
+// @Description Soft-delete a user by ID. The user is hidden until restored and purged once the retention period has passed.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID (UUID)"
+// @Param If-Match header string false "ETag the delete is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as createdBy/updatedBy"
 // @Success 202 {string} string "User deleted successfully"
-// @Failure 400 {object} responses.Response "Invalid user ID"
-// @Failure 404 {object} responses.Response "User not found"
-// @Failure 500 {object} responses.Response "Internal server error"
+// @Failure 400 {object} responses.Problem "Invalid user ID"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
 // @Router /users/{id} [delete]
 func (u *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	idParam := chi.URLParam(r, "id")
@@ -279,25 +455,331 @@ func (u *UserController) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	userID, errId := uuid.Parse(idParam)
 
 	if errId != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "Invalid user id",
-			Errors:  errId.Error(),
-		})
+		responses.WriteBadRequest(w, r, "user id must be a UUID", errId)
+		return
+	}
+
+	version, ok := u.expectedVersion(w, r)
+	if !ok {
 		return
 	}
 
-	_, err2 := u.Delete(r.Context(), userID)
+	_, err2 := u.Delete(r.Context(), userID, version)
 
 	if err2 != nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(responses.Response{
-			Message: "user not found",
-			Errors:  err2.Error(),
-		})
+		responses.WriteError(w, r, err2)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// RestoreUser godoc
+// @Summary Restore user
+// @Description Bring back a soft-deleted user
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param X-Actor header string false "Who is making the change; recorded as createdBy/updatedBy"
+// @Success 200 {object} create.UserResponse "User restored successfully"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid user ID"
+// @Failure 404 {object} responses.Problem "No deleted user with this ID"
+// @Failure 409 {object} responses.Problem "User is not deleted, or its email is now used by another user"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/restore [post]
+func (u *UserController) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	restoredUser, err := u.Restore(r.Context(), userID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag(int(restoredUser.Version)))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(create.UserResponse{
+		UserID:    restoredUser.UserID,
+		Email:     restoredUser.Email,
+		Status:    restoredUser.Status,
+		CreatedAt: restoredUser.CreatedAt,
+		UpdatedAt: restoredUser.UpdatedAt,
+		CreatedBy: restoredUser.CreatedBy,
+		UpdatedBy: restoredUser.UpdatedBy,
+	})
+}
+
+// PurgeUser godoc
+// @Summary Purge user
+// @Description Permanently remove a user, deleted or not. Requires the admin token.
+// @Tags Users
+// @Param id path string true "User ID (UUID)"
+// @Param Authorization header string true "Bearer admin token"
+// @Success 204 "User purged"
+// @Failure 400 {object} responses.Problem "Invalid user ID"
+// @Failure 401 {object} responses.Problem "Admin token missing"
+// @Failure 403 {object} responses.Problem "Not an admin token"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/purge [post]
+func (u *UserController) PurgeUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	if err := u.Purge(r.Context(), userID); err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ActivateUser godoc
+// @Summary Activate user
+// @Description Move a pending, suspended, locked or deactivated user to active
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body status.ChangeRequest true "Why the user is being activated"
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as updatedBy"
+// @Success 200 {object} create.UserResponse "User activated"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid user ID or missing reason"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 409 {object} responses.Problem "The user's current status does not allow this"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/activate [post]
+func (u *UserController) ActivateUser(w http.ResponseWriter, r *http.Request) {
+	u.changeStatus(w, r, domain.UserStatusActive)
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Suspend an active user
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body status.ChangeRequest true "Why the user is being suspended"
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as updatedBy"
+// @Success 200 {object} create.UserResponse "User suspended"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid user ID or missing reason"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 409 {object} responses.Problem "The user's current status does not allow this"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/suspend [post]
+func (u *UserController) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	u.changeStatus(w, r, domain.UserStatusSuspended)
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate user
+// @Description Deactivate a user that is not already deactivated
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param request body status.ChangeRequest true "Why the user is being deactivated"
+// @Param If-Match header string false "ETag the change is conditional on"
+// @Param X-Actor header string false "Who is making the change; recorded as updatedBy"
+// @Success 200 {object} create.UserResponse "User deactivated"
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} responses.Problem "Invalid user ID or missing reason"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 409 {object} responses.Problem "The user's current status does not allow this"
+// @Failure 412 {object} responses.Problem "User changed since the ETag was issued"
+// @Failure 428 {object} responses.Problem "If-Match header required"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/deactivate [post]
+func (u *UserController) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	u.changeStatus(w, r, domain.UserStatusDeactivated)
+}
+
+// GetUserHistory godoc
+// @Summary List user revisions
+// @Description List every recorded change to a user, oldest first, with the fields each one changed. Deleted users keep their history until they are purged.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} history.UserHistoryResponse "Revisions of the user"
+// @Failure 400 {object} responses.Problem "Invalid user ID"
+// @Failure 404 {object} responses.Problem "User not found"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/history [get]
+func (u *UserController) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	revisions, err := u.GetHistory(r.Context(), userID)
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(history.NewUserHistoryResponse(revisions))
+}
+
+// GetUserSnapshot godoc
+// @Summary Get user as of a revision or time
+// @Description Rebuild a user from its history as it was at the given revision or time. Exactly one of revision and at is required; age is derived as of that point.
+// @Tags Users
+// @Produce json
+// @Param id path string true "User ID (UUID)"
+// @Param revision query int false "Revision number"
+// @Param at query string false "RFC 3339 timestamp"
+// @Success 200 {object} history.SnapshotResponse "User as it was"
+// @Failure 400 {object} responses.Problem "Invalid user ID or query parameters"
+// @Failure 404 {object} responses.Problem "User or revision not found, or the user did not exist or was deleted at that time"
+// @Failure 500 {object} responses.Problem "Internal server error"
+// @Failure 503 {object} responses.Problem "Database unavailable"
+// @Failure 504 {object} responses.Problem "Database did not respond in time"
+// @Router /users/{id}/snapshot [get]
+func (u *UserController) GetUserSnapshot(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		responses.WriteBadRequest(w, r, "user id must be a UUID", err)
+		return
+	}
+
+	snapshotRequest, err := history.NewSnapshotRequest(r.URL.Query())
+	if err == nil {
+		err = validator.Validate.Struct(snapshotRequest)
+	}
+
+	if err != nil {
+		responses.WriteBadRequest(w, r, "invalid query parameters", err)
+		return
+	}
+
+	var snapshot domain.User
+	if snapshotRequest.Revision != nil {
+		snapshot, err = u.GetRevision(r.Context(), userID, *snapshotRequest.Revision)
+	} else {
+		snapshot, err = u.GetAsOf(r.Context(), userID, *snapshotRequest.At)
+	}
+
+	if err != nil {
+		responses.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_ = json.NewEncoder(w).Encode(history.SnapshotResponse{
+		Revision:        snapshot.Version,
+		UserResponseDto: get.NewUserResponseDto(snapshot),
+	})
+}
+
+// StreamUserEvents godoc
+// @Summary Stream user events
+// @Description Stream user changes as server-sent events as they are committed by any instance. Each event's id is the event ID, its event field the event type and its data the event as JSON. Reconnect with Last-Event-ID to receive the events missed since; if that event is no longer remembered, a reset event is sent first and the client should reload the users it shows. Idle streams get a comment every so often to keep them open.
+// @Tags Users
+// @Produce text/event-stream
+// @Param type query []string false "Only events of these types" collectionFormat(multi) Enums(user.created, user.updated, user.status_changed, user.deleted, user.restored)
+// @Param userId query string false "Only events about this user (UUID)"
+// @Param Last-Event-ID header string false "ID of the last event received, to resume after"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} responses.Problem "Invalid query parameters"
+// @Failure 500 {object} responses.Problem "Streaming not supported"
+// @Router /users/events [get]
+func (u *UserController) StreamUserEvents(w http.ResponseWriter, r *http.Request) {
+	streamRequest, err := events.NewStreamRequest(r.URL.Query())
+	if err == nil {
+		err = validator.Validate.Struct(streamRequest)
+	}
+
+	if err != nil {
+		responses.WriteBadRequest(w, r, "invalid query parameters", err)
+		return
+	}
+
+	sse, ok := newEventStream(w)
+	if !ok {
+		responses.WriteError(w, r, errors.New("response writer cannot flush"))
+		return
+	}
+
+	subscription, backlog, resumed := u.Events.Subscribe(r.Header.Get("Last-Event-ID"))
+	defer subscription.Close()
+
+	if !resumed {
+		sse.reset()
+	}
+
+	for _, event := range backlog {
+		if streamRequest.Matches(event) {
+			sse.send(event)
+		}
+	}
+
+	if err := sse.flush(); err != nil {
+		return
+	}
+
+	var heartbeat <-chan time.Time
+	if u.Heartbeat > 0 {
+		ticker := time.NewTicker(u.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-subscription.Events():
+			if !open {
+				return
+			}
+			if !streamRequest.Matches(event) {
+				continue
+			}
+			sse.send(event)
+		case <-heartbeat:
+			sse.comment("keep-alive")
+		}
+
+		if err := sse.flush(); err != nil {
+			return
+		}
+	}
+}
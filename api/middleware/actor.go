@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"user-management/api/responses"
+	"user-management/domain"
+)
+
+const (
+	// ActorHeader names whoever is making the request, as asserted by the
+	// gateway in front of the API. It is recorded on the users they write.
+	ActorHeader    = "X-Actor"
+	maxActorLength = 255
+)
+
+// Actor attributes the request's writes to the caller named in X-Actor.
+// Requests without the header are written with an unknown actor.
+func Actor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := actorOf(r)
+		if !ok {
+			responses.WriteBadRequest(w, r, "X-Actor must be at most 255 characters", nil)
+			return
+		}
+
+		if actor == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithActor(r.Context(), actor)))
+	})
+}
+
+// actorOf returns the caller named in X-Actor, or "" when there is none. ok
+// is false when the name is too long to be accepted.
+func actorOf(r *http.Request) (actor string, ok bool) {
+	actor = strings.TrimSpace(r.Header.Get(ActorHeader))
+	if len(actor) > maxActorLength {
+		return "", false
+	}
+	return actor, true
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"user-management/api/responses"
+)
+
+// RequireAdmin only lets through requests that carry token as a bearer
+// token. When no token is configured every request is refused, so admin
+// routes stay closed unless they have been deliberately enabled.
+func RequireAdmin(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			presented, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || presented == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				responses.WriteProblem(w, r, responses.Problem{
+					Type:   responses.ProblemTypeUnauthorized,
+					Status: http.StatusUnauthorized,
+					Detail: "this endpoint requires an admin bearer token",
+				})
+				return
+			}
+
+			if token == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				responses.WriteProblem(w, r, responses.Problem{
+					Type:   responses.ProblemTypeForbidden,
+					Status: http.StatusForbidden,
+					Detail: "the bearer token is not an admin token",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
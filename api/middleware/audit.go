@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+	"user-management/internal/audit"
+	"user-management/internal/logging"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// Audit appends an entry to store for every request that may change state,
+// once it has been handled. Reads are not audited. The response has already
+// been sent by then, so a failed append can only be logged.
+//
+// It relies on RequestID running first. It reads the caller from X-Actor
+// itself, so it can run outside Actor and audit the requests Actor rejects;
+// those are recorded with an unknown actor.
+func Audit(store audit.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			occurredAt := time.Now()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			actor, _ := actorOf(r)
+
+			entry := audit.Entry{
+				OccurredAt: occurredAt,
+				Actor:      actor,
+				SourceIP:   sourceIP(r),
+				RequestID:  chimiddleware.GetReqID(r.Context()),
+				Method:     r.Method,
+				Route:      routePattern(r),
+				TargetUser: targetUser(r, ww.Header()),
+				Status:     status,
+				Outcome:    audit.OutcomeOf(status),
+			}
+
+			// Record the call even if the client has gone away.
+			if _, err := store.Append(context.WithoutCancel(r.Context()), entry); err != nil {
+				logging.FromContext(r.Context()).Error("appending audit entry", "error", err)
+			}
+		})
+	}
+}
+
+// sourceIP is the address the connection came from. Forwarding headers are
+// ignored because clients can set them to anything.
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return r.URL.Path
+}
+
+// targetUser is the user named in the URL or, for creation, the one the
+// response points to. Calls outside /users target no user.
+func targetUser(r *http.Request, header http.Header) uuid.UUID {
+	if !strings.HasPrefix(routePattern(r), "/users") {
+		return uuid.Nil
+	}
+
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		id = strings.TrimPrefix(header.Get("Location"), "/users/")
+	}
+
+	target, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil
+	}
+	return target
+}
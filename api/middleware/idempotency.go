@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+	"user-management/api/responses"
+	"user-management/domain"
+	"user-management/internal/logging"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	ProblemTypeIdempotencyKey = "/problems/idempotency-key-reused"
+)
+
+// replayedHeaders are the response headers stored alongside the body so a
+// replay looks like the original response.
+var replayedHeaders = []string{"Content-Type", "Content-Language", "ETag", "Location"}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to
+// retry. The first request with a key is processed and its response stored
+// for ttl; later requests with the same key and body get the stored
+// response back, while reusing the key for a different body is rejected.
+// Server errors are not stored so the client can retry them. The key is
+// held for lease while the request runs, after which a retry may take it
+// over, so lease must outlast the request deadline. Bodies are buffered to
+// be fingerprinted and rejected beyond maxBodyBytes.
+func Idempotency(store domain.IdempotencyRepository, ttl time.Duration, lease time.Duration, maxBodyBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				responses.WriteBadRequest(w, r, "Idempotency-Key must be at most 255 characters", nil)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+				responses.WriteProblem(w, r, responses.Problem{
+					Type:   responses.ProblemTypePayloadTooLarge,
+					Status: http.StatusRequestEntityTooLarge,
+					Detail: fmt.Sprintf("request body must be at most %d bytes", maxBytesErr.Limit),
+				})
+				return
+			}
+			if err != nil {
+				responses.WriteBadRequest(w, r, "request body could not be read", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			hash := requestHash(r, body)
+
+			claim, stored, err := store.Claim(r.Context(), key, hash, ttl, lease)
+			if err != nil {
+				responses.WriteError(w, r, err)
+				return
+			}
+
+			if claim == uuid.Nil {
+				replay(w, r, hash, stored)
+				return
+			}
+
+			// Finish the bookkeeping even if the client has gone away.
+			storeCtx := context.WithoutCancel(r.Context())
+
+			completed := false
+			defer func() {
+				if !completed {
+					if err := store.Release(storeCtx, key, claim); err != nil {
+						logging.FromContext(r.Context()).Error("releasing idempotency key", "error", err)
+					}
+				}
+			}()
+
+			var recorded bytes.Buffer
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&recorded)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if status >= http.StatusInternalServerError {
+				return
+			}
+
+			header := make(map[string][]string)
+			for _, name := range replayedHeaders {
+				if values := ww.Header().Values(name); len(values) > 0 {
+					header[name] = values
+				}
+			}
+
+			err = store.Complete(storeCtx, key, claim, domain.IdempotentResponse{
+				RequestHash: hash,
+				StatusCode:  status,
+				Header:      header,
+				Body:        recorded.Bytes(),
+			})
+			if err != nil {
+				logging.FromContext(r.Context()).Error("storing idempotent response", "error", err)
+				return
+			}
+
+			completed = true
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, r *http.Request, hash string, stored domain.IdempotentResponse) {
+	switch {
+	case stored.RequestHash != hash:
+		responses.WriteProblem(w, r, responses.Problem{
+			Type:   ProblemTypeIdempotencyKey,
+			Status: http.StatusUnprocessableEntity,
+			Detail: "Idempotency-Key was already used for a different request",
+		})
+	case stored.StatusCode == 0:
+		responses.WriteProblem(w, r, responses.Problem{
+			Type:   responses.ProblemTypeConflict,
+			Status: http.StatusConflict,
+			Detail: "a request with this Idempotency-Key is still being processed",
+		})
+	default:
+		for name, values := range stored.Header {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(stored.StatusCode)
+		_, _ = w.Write(stored.Body)
+	}
+}
+
+// requestHash fingerprints the parts of a request that must not change when
+// it is retried under the same key.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+	"user-management/internal/metrics"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Metrics records each request's status and latency under its route pattern.
+//
+// It must run after routing for the route to be known, as it does in a route
+// group; unmatched paths never reach it, so they cannot add series.
+func Metrics(recorder *metrics.HTTP) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			recorder.Observe(r.Method, routePattern(r), status, time.Since(started))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+	"user-management/internal/logging"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestLogger puts a logger carrying the request's ID, trace and route in
+// the request context, for anything logged while handling it, and logs each
+// request once it has been handled. Server errors are logged at error level.
+//
+// It relies on RequestID, and Trace if traces should be linked, running
+// first, and must run after routing for the route pattern and user ID to be
+// known, as it does in a route group.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started := time.Now()
+
+			requestLogger := logger.With(
+				slog.String("request_id", chimiddleware.GetReqID(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("route", routePattern(r)),
+			)
+			if span := trace.SpanContextFromContext(r.Context()); span.IsValid() {
+				requestLogger = requestLogger.With(
+					slog.String("trace_id", span.TraceID().String()),
+					slog.String("span_id", span.SpanID().String()),
+				)
+			}
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(logging.WithLogger(r.Context(), requestLogger)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			attrs := []slog.Attr{
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(started)),
+			}
+			if user := targetUser(r, ww.Header()); user != uuid.Nil {
+				attrs = append(attrs, slog.String("user_id", user.String()))
+			}
+
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			requestLogger.LogAttrs(r.Context(), level, "request", attrs...)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace records a server span for each request, named after its method and
+// route, continuing the trace named in an incoming traceparent header.
+//
+// It must run after routing for the route to be known, as it does in a route
+// group.
+func Trace(next http.Handler) http.Handler {
+	annotated := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace.SpanFromContext(r.Context()).SetAttributes(semconv.HTTPRoute(routePattern(r)))
+		next.ServeHTTP(w, r)
+	})
+
+	return otelhttp.NewHandler(annotated, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + routePattern(r)
+		}),
+	)
+}
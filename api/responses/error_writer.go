@@ -0,0 +1,116 @@
+package responses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-management/domain"
+	"user-management/internal/logging"
+	requestValidator "user-management/internal/validator"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type problemKind struct {
+	status      int
+	problemType string
+}
+
+var problemByKind = map[domain.ErrorKind]problemKind{
+	domain.ErrorKindNotFound:    {http.StatusNotFound, ProblemTypeNotFound},
+	domain.ErrorKindConflict:    {http.StatusConflict, ProblemTypeConflict},
+	domain.ErrorKindValidation:  {http.StatusUnprocessableEntity, ProblemTypeValidation},
+	domain.ErrorKindUnavailable: {http.StatusServiceUnavailable, ProblemTypeUnavailable},
+	domain.ErrorKindTimeout:     {http.StatusGatewayTimeout, ProblemTypeTimeout},
+	domain.ErrorKindCanceled:    {StatusClientClosedRequest, ProblemTypeCanceled},
+
+	domain.ErrorKindPreconditionFailed: {http.StatusPreconditionFailed, ProblemTypePreconditionFailed},
+}
+
+// WriteError writes err as a problem response, choosing the status code
+// from its domain error kind. Errors without a kind are reported as a 500
+// without exposing their text to the client; they are logged, with the
+// request's correlation, in its stead.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	// Once the client has gone away, whatever failed is reported as the
+	// cancellation; Postgres reports statements cancelled on its behalf as
+	// timeouts.
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		err = domain.NewCanceledError("request was canceled", err)
+	}
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == domain.ErrorKindInternal {
+		logging.FromContext(r.Context()).Error("internal error", "error", err)
+		WriteProblem(w, r, Problem{
+			Type:   ProblemTypeInternal,
+			Status: http.StatusInternalServerError,
+			Detail: "unexpected error",
+		})
+		return
+	}
+
+	kind := problemByKind[domainErr.Kind]
+	switch kind.status {
+	case http.StatusServiceUnavailable:
+		logging.FromContext(r.Context()).Warn("dependency unavailable", "error", err)
+	case http.StatusGatewayTimeout:
+		logging.FromContext(r.Context()).Warn("timed out", "error", err)
+	case StatusClientClosedRequest:
+		logging.FromContext(r.Context()).Debug("request canceled by client", "error", err)
+	}
+
+	WriteProblem(w, r, Problem{
+		Type:   kind.problemType,
+		Status: kind.status,
+		Detail: domainErr.Message,
+	})
+}
+
+// WriteBadRequest reports a malformed request, such as an unparsable ID or
+// body. Validation failures are broken down into one entry per field.
+func WriteBadRequest(w http.ResponseWriter, r *http.Request, detail string, err error) {
+	problem := Problem{
+		Type:   ProblemTypeBadRequest,
+		Status: http.StatusBadRequest,
+		Detail: detail,
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		translator := requestValidator.TranslatorFor(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", translator.Locale())
+
+		problem.Type = ProblemTypeValidation
+		problem.Title = "Validation failed"
+		for _, fieldErr := range validationErrors {
+			problem.Errors = append(problem.Errors, FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Param:   fieldErr.Param(),
+				Message: fieldErr.Translate(translator),
+			})
+		}
+	} else if err != nil {
+		problem.Detail = detail + ": " + err.Error()
+	}
+
+	WriteProblem(w, r, problem)
+}
+
+// WriteProblem writes problem, defaulting its title to the status text and
+// its instance to the request path.
+func WriteProblem(w http.ResponseWriter, r *http.Request, problem Problem) {
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	if problem.Instance == "" && r != nil {
+		problem.Instance = r.URL.Path
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
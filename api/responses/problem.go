@@ -0,0 +1,47 @@
+package responses
+
+// Problem is an RFC 7807 problem details object, written with the
+// application/problem+json media type.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one invalid request field: its JSON name, the
+// validation rule it failed, that rule's parameter, if any, and a message in
+// the language negotiated from the request's Accept-Language header.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+const ProblemContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard status recorded for requests
+// the client abandoned before they completed.
+const StatusClientClosedRequest = 499
+
+// Problem type URIs, one per class of failure the API reports.
+const (
+	ProblemTypeBadRequest   = "/problems/bad-request"
+	ProblemTypeValidation   = "/problems/validation"
+	ProblemTypeNotFound     = "/problems/not-found"
+	ProblemTypeConflict     = "/problems/conflict"
+	ProblemTypeUnavailable  = "/problems/unavailable"
+	ProblemTypeTimeout      = "/problems/timeout"
+	ProblemTypeCanceled     = "/problems/canceled"
+	ProblemTypeInternal     = "/problems/internal"
+	ProblemTypeUnauthorized = "/problems/unauthorized"
+	ProblemTypeForbidden    = "/problems/forbidden"
+
+	ProblemTypePreconditionFailed   = "/problems/precondition-failed"
+	ProblemTypePreconditionRequired = "/problems/precondition-required"
+	ProblemTypeUnsupportedMediaType = "/problems/unsupported-media-type"
+	ProblemTypePayloadTooLarge      = "/problems/payload-too-large"
+)
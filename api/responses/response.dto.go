@@ -1,6 +0,0 @@
-package responses
-
-type Response struct {
-	Message string `json:"message"`
-	Errors  string `json:"errors"`
-}
@@ -0,0 +1,16 @@
+package audits
+
+import (
+	"user-management/api/controller/auditlog"
+	"user-management/api/middleware"
+	"user-management/bootstrap"
+	"user-management/internal/audit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func AuditRouter(env *bootstrap.Env, store audit.Store, router chi.Router) {
+	ac := &auditlog.AuditController{Store: store}
+
+	router.With(middleware.RequireAdmin(env.AdminToken)).Get("/audit", ac.ListEntries)
+}
@@ -0,0 +1,16 @@
+package healthchecks
+
+import (
+	"user-management/api/controller/healthcheck"
+	"user-management/internal/health"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func HealthRouter(checker *health.Checker, router chi.Router) {
+	hc := &healthcheck.HealthController{Checker: checker}
+
+	router.Get("/healthz", hc.Live)
+	router.Get("/readyz", hc.Ready)
+	router.Get("/health", hc.Health)
+}
@@ -1,17 +1,63 @@
 package route
 
 import (
+	"log/slog"
+	"net/http"
+	"user-management/api/middleware"
+	"user-management/api/route/audits"
+	"user-management/api/route/healthchecks"
 	"user-management/api/route/users"
+	"user-management/api/route/webhooks"
 	"user-management/bootstrap"
+	_ "user-management/docs"
+	"user-management/internal/health"
+	"user-management/internal/metrics"
+	"user-management/internal/stream"
+	"user-management/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func Setup(env *bootstrap.Env, connectionPool *pgxpool.Pool, router *chi.Mux) {
+func Setup(env *bootstrap.Env, logger *slog.Logger, connectionPool *pgxpool.Pool, events *stream.Broker, checker *health.Checker, observed *metrics.Metrics, router *chi.Mux) {
+
+	router.Use(middleware.RequestID)
+
+	router.Get("/swagger/*", httpSwagger.WrapHandler)
+
+	// Probes and scrapes are not measured, traced, logged, attributed to an
+	// actor or audited.
+	healthchecks.HealthRouter(checker, router)
+
+	// A collector that fails, such as the user counts while the database is
+	// down, is left out rather than failing the whole scrape.
+	router.Method(http.MethodGet, "/metrics", promhttp.HandlerFor(observed.Registry, promhttp.HandlerOpts{
+		ErrorHandling: promhttp.ContinueOnError,
+	}))
+
+	if env.AuditHashKey == "" {
+		logger.Warn("AUDIT_HASH_KEY is not set, the audit log chain only detects accidental changes")
+	}
+	auditLog := repository.NewAuditRepository(connectionPool, []byte(env.AuditHashKey))
 
 	// Public APIs
 	router.Group(func(r chi.Router) {
-		users.UserRouter(env, connectionPool, router)
+		r.Use(middleware.Metrics(observed.HTTP))
+		r.Use(middleware.Trace)
+		r.Use(middleware.RequestLogger(logger))
+		// Audit runs outside Actor so that requests it rejects are audited.
+		r.Use(middleware.Audit(auditLog))
+		r.Use(middleware.Actor)
+
+		users.EventStreamRouter(env, events, r)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(env.ContextTimeout))
+			users.UserRouter(env, logger, connectionPool, observed.Repository, r)
+			audits.AuditRouter(env, auditLog, r)
+			webhooks.WebhookRouter(env, repository.NewWebhookRepository(connectionPool), r)
+		})
 	})
 }
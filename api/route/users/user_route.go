@@ -1,24 +1,75 @@
 package users
 
 import (
+	"crypto/rand"
+	"log/slog"
 	"user-management/api/controller/user"
+	"user-management/api/middleware"
 	"user-management/bootstrap"
+	"user-management/internal/cursor"
+	"user-management/internal/metrics"
+	"user-management/internal/stream"
 	"user-management/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func UserRouter(env *bootstrap.Env, connectionPool *pgxpool.Pool, router *chi.Mux) {
-	ur := repository.NewUserRepository(connectionPool)
+func UserRouter(env *bootstrap.Env, logger *slog.Logger, connectionPool *pgxpool.Pool, repositoryMetrics *metrics.Repository, router chi.Router) {
+	ur := repository.NewInstrumentedUserRepository(repository.NewUserRepository(connectionPool), repositoryMetrics)
 	uc := &user.UserController{
 		UserRepository: ur,
 		Env:            env,
+		Cursors:        cursor.NewCodec(cursorSecret(env, logger)),
 	}
 
-	router.Post("/users", uc.CreateUser)
+	idempotency := middleware.Idempotency(
+		repository.NewIdempotencyRepository(connectionPool),
+		env.IdempotencyKeyTTL,
+		env.IdempotencyClaimLease,
+		env.IdempotencyMaxBodyBytes,
+	)
+
+	router.With(idempotency).Post("/users", uc.CreateUser)
 	router.Get("/users", uc.GetAllUsers)
 	router.Get("/users/{id}", uc.GetUserById)
 	router.Put("/users/{id}", uc.UpdateUser)
+	router.Patch("/users/{id}", uc.PatchUser)
 	router.Delete("/users/{id}", uc.DeleteUser)
+	router.Get("/users/{id}/history", uc.GetUserHistory)
+	router.Get("/users/{id}/snapshot", uc.GetUserSnapshot)
+	router.Post("/users/{id}/restore", uc.RestoreUser)
+	router.Post("/users/{id}/activate", uc.ActivateUser)
+	router.Post("/users/{id}/suspend", uc.SuspendUser)
+	router.Post("/users/{id}/deactivate", uc.DeactivateUser)
+	router.With(middleware.RequireAdmin(env.AdminToken)).Post("/users/{id}/purge", uc.PurgeUser)
+}
+
+// EventStreamRouter serves the live user event stream. Streams stay open for
+// as long as the client listens, so it must not be mounted behind a request
+// deadline.
+func EventStreamRouter(env *bootstrap.Env, events *stream.Broker, router chi.Router) {
+	uc := &user.UserController{
+		Env:       env,
+		Events:    events,
+		Heartbeat: env.EventStreamHeartbeat,
+	}
+
+	router.Get("/users/events", uc.StreamUserEvents)
+}
+
+// cursorSecret returns the configured signing key for continuation tokens,
+// falling back to a random one so tokens still cannot be forged. Tokens
+// signed with a random key do not survive a restart.
+func cursorSecret(env *bootstrap.Env, logger *slog.Logger) []byte {
+	if env.CursorSecret != "" {
+		return []byte(env.CursorSecret)
+	}
+
+	logger.Warn("CURSOR_SECRET is not set; continuation tokens will not survive restarts")
+
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+
+	return secret
 }
@@ -0,0 +1,26 @@
+package webhooks
+
+import (
+	"user-management/api/controller/subscription"
+	"user-management/api/middleware"
+	"user-management/bootstrap"
+	"user-management/internal/webhook"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func WebhookRouter(env *bootstrap.Env, store webhook.Store, router chi.Router) {
+	sc := &subscription.SubscriptionController{Store: store}
+
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAdmin(env.AdminToken))
+
+		r.Post("/webhooks", sc.CreateSubscription)
+		r.Get("/webhooks", sc.ListSubscriptions)
+		r.Get("/webhooks/{id}", sc.GetSubscription)
+		r.Delete("/webhooks/{id}", sc.DeleteSubscription)
+		r.Get("/webhooks/{id}/deliveries", sc.ListDeliveries)
+		r.Get("/webhooks/{id}/deliveries/{deliveryId}/attempts", sc.ListAttempts)
+		r.Post("/webhooks/{id}/deliveries/{deliveryId}/replay", sc.ReplayDelivery)
+	})
+}
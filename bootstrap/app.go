@@ -1,15 +1,21 @@
 package bootstrap
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 type Application struct {
 	Env            *Env
+	Logger         *slog.Logger
 	ConnectionPool *pgxpool.Pool
 }
 
 func App() Application {
 	app := &Application{}
 	app.Env = NewEnv()
+	app.Logger = NewLogger(app.Env)
 	app.ConnectionPool = GetConnectionPool(app.Env)
 	return *app
 }
@@ -4,19 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"strconv"
 	"time"
+	"user-management/internal/tracing"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	pool "github.com/jackc/pgx/v5/pgxpool"
 )
 
-func GetConnectionPool(env *Env) *pool.Pool {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	connectionString := fmt.Sprintf(
+// DatabaseURL returns the connection string for the configured database.
+func DatabaseURL(env *Env) string {
+	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		env.DBUser,
 		env.DBPass,
@@ -25,10 +25,30 @@ func GetConnectionPool(env *Env) *pool.Pool {
 		env.DBName,
 		env.DBSSLMode,
 	)
+}
+
+func GetConnectionPool(env *Env) *pool.Pool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	config, err := pool.ParseConfig(DatabaseURL(env))
+	if err != nil {
+		log.Fatal("Invalid database configuration:", err)
+	}
+	config.ConnConfig.Tracer = tracing.QueryTracer{}
 
-	var err error
+	if env.DBStatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(env.DBStatementTimeout.Milliseconds(), 10)
+	}
+
+	// When a request's deadline passes mid-query, ask Postgres to cancel the
+	// statement so that it stops working on it, and keep the connection. It
+	// is only closed if Postgres has not answered a second later.
+	config.ConnConfig.BuildContextWatcherHandler = func(conn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: conn, DeadlineDelay: time.Second}
+	}
 
-	db, err := pool.New(ctx, connectionString)
+	db, err := pool.NewWithConfig(ctx, config)
 	if err != nil {
 		log.Fatal("Unable to create connection pool:", err)
 	}
@@ -37,9 +57,7 @@ func GetConnectionPool(env *Env) *pool.Pool {
 		log.Fatal("Database connection failed:", err)
 	}
 
-	fmt.Println("Successfully connected to database")
-	migrateData(connectionString)
-	fmt.Println("Successfully migration process done")
+	slog.Info("Connected to database", "host", env.DBHost, "database", env.DBName)
 
 	return db
 }
@@ -47,17 +65,3 @@ func GetConnectionPool(env *Env) *pool.Pool {
 func CloseConnectionPool(db *pool.Pool) {
 	db.Close()
 }
-
-func migrateData(connectionString string) {
-	db, err := migrate.New("file://./migrations", connectionString)
-
-	if err != nil {
-		log.Fatal(err)
-	}
-	err = db.Up()
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	db.Close()
-}
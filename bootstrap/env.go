@@ -8,18 +8,82 @@ import (
 )
 
 type Env struct {
-	DBHost         string        `mapstructure:"DB_HOST"`
-	DBPort         string        `mapstructure:"DB_PORT"`
-	DBUser         string        `mapstructure:"DB_USER"`
-	DBPass         string        `mapstructure:"DB_PASS"`
-	DBName         string        `mapstructure:"DB_NAME"`
-	DBSSLMode      string        `mapstructure:"DB_SSLMODE"`
-	ContextTimeout time.Duration `mapstructure:"CONTEXT_TIMEOUT"`
+	ServerAddress           string        `mapstructure:"SERVER_ADDRESS"`
+	LogFormat               string        `mapstructure:"LOG_FORMAT"`
+	LogLevel                string        `mapstructure:"LOG_LEVEL"`
+	TracingExporter         string        `mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint         string        `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingServiceName      string        `mapstructure:"TRACING_SERVICE_NAME"`
+	TracingSampleRatio      float64       `mapstructure:"TRACING_SAMPLE_RATIO"`
+	ShutdownTimeout         time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	ShutdownDrainDelay      time.Duration `mapstructure:"SHUTDOWN_DRAIN_DELAY"`
+	HealthCheckTimeout      time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+	PoolSaturation          float64       `mapstructure:"POOL_SATURATION_THRESHOLD"`
+	DBHost                  string        `mapstructure:"DB_HOST"`
+	DBPort                  string        `mapstructure:"DB_PORT"`
+	DBUser                  string        `mapstructure:"DB_USER"`
+	DBPass                  string        `mapstructure:"DB_PASS"`
+	DBName                  string        `mapstructure:"DB_NAME"`
+	DBSSLMode               string        `mapstructure:"DB_SSLMODE"`
+	DBStatementTimeout      time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	MigrateOnStart          bool          `mapstructure:"MIGRATE_ON_START"`
+	ContextTimeout          time.Duration `mapstructure:"CONTEXT_TIMEOUT"`
+	CursorSecret            string        `mapstructure:"CURSOR_SECRET"`
+	RequireIfMatch          bool          `mapstructure:"REQUIRE_IF_MATCH"`
+	IdempotencyKeyTTL       time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	IdempotencyClaimLease   time.Duration `mapstructure:"IDEMPOTENCY_CLAIM_LEASE"`
+	IdempotencyMaxBodyBytes int64         `mapstructure:"IDEMPOTENCY_MAX_BODY_BYTES"`
+	AdminToken              string        `mapstructure:"ADMIN_TOKEN"`
+	AuditHashKey            string        `mapstructure:"AUDIT_HASH_KEY"`
+	UserRetention           time.Duration `mapstructure:"USER_RETENTION"`
+	PurgeInterval           time.Duration `mapstructure:"PURGE_INTERVAL"`
+	OutboxPollInterval      time.Duration `mapstructure:"OUTBOX_POLL_INTERVAL"`
+	OutboxBatchSize         int           `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxClaimLease        time.Duration `mapstructure:"OUTBOX_CLAIM_LEASE"`
+	OutboxMaxAttempts       int           `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+	OutboxRetention         time.Duration `mapstructure:"OUTBOX_RETENTION"`
+	WebhookPollInterval     time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookBatchSize        int           `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	WebhookTimeout          time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts      int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryDelay       time.Duration `mapstructure:"WEBHOOK_RETRY_DELAY"`
+	EventStreamReplay       int           `mapstructure:"EVENT_STREAM_REPLAY"`
+	EventStreamHeartbeat    time.Duration `mapstructure:"EVENT_STREAM_HEARTBEAT"`
 }
 
 func NewEnv() *Env {
 	env := Env{}
 	viper.SetConfigFile(".env")
+	viper.SetDefault("SERVER_ADDRESS", ":8080")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_SERVICE_NAME", "user-management")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 15*time.Second)
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", 5*time.Second)
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+	viper.SetDefault("POOL_SATURATION_THRESHOLD", 0.9)
+	viper.SetDefault("DB_STATEMENT_TIMEOUT", 30*time.Second)
+	viper.SetDefault("MIGRATE_ON_START", true)
+	viper.SetDefault("CONTEXT_TIMEOUT", 60*time.Second)
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	viper.SetDefault("IDEMPOTENCY_CLAIM_LEASE", 2*time.Minute)
+	viper.SetDefault("IDEMPOTENCY_MAX_BODY_BYTES", 1<<20)
+	viper.SetDefault("USER_RETENTION", 30*24*time.Hour)
+	viper.SetDefault("PURGE_INTERVAL", time.Hour)
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", time.Second)
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("OUTBOX_CLAIM_LEASE", time.Minute)
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	viper.SetDefault("OUTBOX_RETENTION", 7*24*time.Hour)
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", time.Second)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_DELAY", 30*time.Second)
+	viper.SetDefault("EVENT_STREAM_REPLAY", 1000)
+	viper.SetDefault("EVENT_STREAM_HEARTBEAT", 15*time.Second)
 
 	_ = viper.ReadInConfig()
 	err := viper.Unmarshal(&env)
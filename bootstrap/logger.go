@@ -0,0 +1,21 @@
+package bootstrap
+
+import (
+	"log"
+	"log/slog"
+	"os"
+	"user-management/internal/logging"
+)
+
+// NewLogger returns the configured logger and makes it the default, so the
+// standard log package writes through it too.
+func NewLogger(env *Env) *slog.Logger {
+	logger, err := logging.New(os.Stdout, env.LogFormat, env.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	slog.SetDefault(logger)
+
+	return logger
+}
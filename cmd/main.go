@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+	"os"
+	"user-management/bootstrap"
+)
+
+// @title User Management API
+// @version 1.0
+// @description REST API for user management
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name API Support
+// @contact.email support@example.com
+
+// @license.name MIT
+// @license.url https://opensource.org/licenses/MIT
+
+// @host localhost:8080
+// @BasePath /
+func main() {
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	run, ok := commands[command]
+	if !ok {
+		log.Fatalf("unknown command %q, expected serve, migrate or verify-audit", command)
+	}
+
+	app := bootstrap.App()
+
+	if err := run(&app, args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// commands are the subcommands the binary accepts; it serves the API when
+// none is given.
+var commands = map[string]func(app *bootstrap.Application, args []string) error{
+	"serve":        runServer,
+	"migrate":      runMigrate,
+	"verify-audit": runVerifyAudit,
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"user-management/bootstrap"
+	"user-management/internal/migration"
+)
+
+const migrateUsage = "usage: migrate up | down N | goto VERSION | version | force VERSION | status"
+
+// runMigrate applies, reverts or reports on the embedded migrations.
+func runMigrate(app *bootstrap.Application, args []string) error {
+	defer app.CloseDBConnectionPool()
+
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := migration.New(bootstrap.DatabaseURL(app.Env))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = migrator.Close() }()
+
+	switch command, operands := args[0], args[1:]; {
+	case command == "up" && len(operands) == 0:
+		err = migrator.Up()
+	case command == "down" && len(operands) == 1:
+		var steps int
+		if steps, err = strconv.Atoi(operands[0]); err == nil {
+			err = migrator.Down(steps)
+		}
+	case command == "goto" && len(operands) == 1:
+		var version uint64
+		if version, err = strconv.ParseUint(operands[0], 10, 0); err == nil {
+			err = migrator.Goto(uint(version))
+		}
+	case command == "force" && len(operands) == 1:
+		var version int
+		if version, err = strconv.Atoi(operands[0]); err == nil {
+			err = migrator.Force(version)
+		}
+	case command == "version" && len(operands) == 0:
+		return printVersion(migrator)
+	case command == "status" && len(operands) == 0:
+		return printStatus(migrator)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printVersion(migrator)
+}
+
+func printVersion(migrator *migration.Migrator) error {
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Dirty:
+		fmt.Printf("Schema version %d (dirty)\n", status.Version)
+	case status.Version == 0:
+		fmt.Println("No migrations applied")
+	default:
+		fmt.Printf("Schema version %d\n", status.Version)
+	}
+
+	return nil
+}
+
+// printStatus lists every embedded migration and whether it is applied.
+func printStatus(migrator *migration.Migrator) error {
+	if err := printVersion(migrator); err != nil {
+		return err
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	available, err := migration.Available()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range available {
+		state := "pending"
+		switch {
+		case m.Version == status.Version && status.Dirty:
+			state = "dirty"
+		case m.Version <= status.Version:
+			state = "applied"
+		}
+		fmt.Printf("  %03d %-40s %s\n", m.Version, m.Name, state)
+	}
+
+	if err := status.Check(); err != nil {
+		fmt.Println(err)
+	}
+
+	return nil
+}
+
+// prepareSchema applies pending migrations if configured to, then makes
+// sure the schema is one this binary can serve.
+func prepareSchema(env *bootstrap.Env) error {
+	migrator, err := migration.New(bootstrap.DatabaseURL(env))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = migrator.Close() }()
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	// Migrating a dirty or newer schema would fail anyway; say why instead.
+	if err := status.Check(); err != nil {
+		return fmt.Errorf("refusing to serve: %w", err)
+	}
+
+	if env.MigrateOnStart && status.Version < status.Latest {
+		if err := migrator.Up(); err != nil {
+			return fmt.Errorf("migrating schema: %w", err)
+		}
+
+		if status, err = migrator.Status(); err != nil {
+			return err
+		}
+	}
+
+	if status.Version < status.Latest {
+		log.Printf("Schema version %d is behind this binary (%d); run migrate up", status.Version, status.Latest)
+	}
+
+	return nil
+}
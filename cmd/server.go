@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+	"user-management/api/route"
+	"user-management/bootstrap"
+	"user-management/internal/health"
+	"user-management/internal/metrics"
+	"user-management/internal/outbox"
+	"user-management/internal/purge"
+	"user-management/internal/stream"
+	"user-management/internal/tracing"
+	"user-management/internal/validator"
+	"user-management/internal/webhook"
+	"user-management/repository"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// runServer checks the schema, migrating it first unless disabled, and serves
+// the API until SIGINT or SIGTERM is received. It then reports not ready, ends
+// open event streams, drains in-flight requests and stops the background
+// purge, outbox dispatch, webhook delivery and event listener before
+// releasing the database connection pool.
+func runServer(app *bootstrap.Application, args []string) error {
+	defer app.CloseDBConnectionPool()
+
+	if err := prepareSchema(app.Env); err != nil {
+		return err
+	}
+
+	validator.Init()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     app.Env.TracingExporter,
+		OTLPEndpoint: app.Env.TracingEndpoint,
+		ServiceName:  app.Env.TracingServiceName,
+		SampleRatio:  app.Env.TracingSampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+	// Flush spans once the server and background work have stopped.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Flushing traces", "error", err)
+		}
+	}()
+
+	events := stream.NewBroker(app.Env.EventStreamReplay)
+
+	checker := &health.Checker{Timeout: app.Env.HealthCheckTimeout}
+	checker.Add("database", health.Database(app.ConnectionPool))
+	checker.Add("schema", health.Schema(app.ConnectionPool))
+	checker.Add("pool", health.Pool(app.ConnectionPool, app.Env.PoolSaturation))
+
+	// Counting users is bounded like a health check so a slow database cannot
+	// stall scrapes.
+	observed := metrics.New()
+	observed.Registry.MustRegister(
+		metrics.NewPoolCollector(app.ConnectionPool),
+		metrics.NewUserCollector(repository.NewUserRepository(app.ConnectionPool), app.Env.HealthCheckTimeout),
+	)
+
+	router := chi.NewRouter()
+	route.Setup(app.Env, app.Logger, app.ConnectionPool, events, checker, observed, router)
+
+	server := &http.Server{
+		Addr:    app.Env.ServerAddress,
+		Handler: router,
+	}
+	// Streams never go idle, so Shutdown would otherwise wait them out.
+	server.RegisterOnShutdown(events.Close)
+
+	outboxStore := repository.NewOutboxRepository(app.ConnectionPool)
+
+	purger := &purge.Purger{
+		Users:           repository.NewInstrumentedUserRepository(repository.NewUserRepository(app.ConnectionPool), observed.Repository),
+		IdempotencyKeys: repository.NewIdempotencyRepository(app.ConnectionPool),
+		Outbox:          outboxStore,
+		Retention:       app.Env.UserRetention,
+		OutboxRetention: app.Env.OutboxRetention,
+		Interval:        app.Env.PurgeInterval,
+	}
+
+	webhooks := repository.NewWebhookRepository(app.ConnectionPool)
+
+	dispatcher := &outbox.Dispatcher{
+		Store:       outboxStore,
+		Publisher:   webhook.Fanout{Store: webhooks},
+		Interval:    app.Env.OutboxPollInterval,
+		BatchSize:   app.Env.OutboxBatchSize,
+		Lease:       app.Env.OutboxClaimLease,
+		MaxAttempts: app.Env.OutboxMaxAttempts,
+		Metrics:     observed.Outbox,
+	}
+
+	deliverer := &webhook.Deliverer{
+		Store: webhooks,
+		// Passes the delivery's trace on to subscribers in traceparent.
+		Client:      &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		Interval:    app.Env.WebhookPollInterval,
+		BatchSize:   app.Env.WebhookBatchSize,
+		Timeout:     app.Env.WebhookTimeout,
+		MaxAttempts: app.Env.WebhookMaxAttempts,
+		RetryDelay:  app.Env.WebhookRetryDelay,
+	}
+
+	listener := &stream.Listener{
+		Pool:       app.ConnectionPool,
+		Broker:     events,
+		RetryDelay: time.Second,
+	}
+
+	// Background work must stop before the deferred pool close above runs.
+	defer runInBackground(purger.Run)()
+	defer runInBackground(dispatcher.Run)()
+	defer runInBackground(deliverer.Run)()
+	defer runInBackground(listener.Run)()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "address", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server stopped unexpectedly: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	stop()
+	slog.Info("Shutting down, draining in-flight requests")
+
+	// Fail readiness first, and keep serving while probes notice, so load
+	// balancers stop sending new requests before connections are refused.
+	checker.Drain()
+	time.Sleep(app.Env.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.Env.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+
+	slog.Info("Server stopped")
+
+	return nil
+}
+
+// runInBackground starts run in a goroutine and returns a function that
+// cancels it and waits for it to return.
+func runInBackground(run func(ctx context.Context)) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"user-management/bootstrap"
+	"user-management/internal/audit"
+	"user-management/repository"
+)
+
+// runVerifyAudit walks the audit log hash chain from the first entry and
+// fails at the first entry that has been tampered with.
+func runVerifyAudit(app *bootstrap.Application, args []string) error {
+	defer app.CloseDBConnectionPool()
+
+	key := []byte(app.Env.AuditHashKey)
+
+	checked, err := audit.Verify(context.Background(), repository.NewAuditRepository(app.ConnectionPool, key), key)
+	if err != nil {
+		return fmt.Errorf("audit log verification failed after %d intact entries: %w", checked, err)
+	}
+
+	log.Printf("Audit log intact, %d entries verified", checked)
+
+	return nil
+}
@@ -23,9 +23,193 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "description": "List recorded mutating API calls, oldest first, optionally filtered. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only calls made by this actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls targeting this user (UUID)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "success",
+                            "failure"
+                        ],
+                        "type": "string",
+                        "description": "Only calls with this outcome",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls after this RFC 3339 timestamp",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls before this RFC 3339 timestamp",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Continue after the entry with this ID",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/auditlog.EntryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Run every check and report each one's status, duration, error and details such as connection pool saturation, for operators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Detailed health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report that the process is running. It does not check dependencies, so a failing database does not get the instance restarted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.StatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Report whether the instance should receive traffic: the database answers, the schema matches this release and the instance is not shutting down. A degraded dependency still counts as ready.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.StatusResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.StatusResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users": {
             "get": {
-                "description": "Retrieve all users",
+                "description": "Retrieve a page of users, optionally filtered and sorted. Pages are addressed either by offset or by the opaque nextCursor token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,20 +220,145 @@ const docTemplate = `{
                     "Users"
                 ],
                 "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of users to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "firstName",
+                            "lastName",
+                            "email",
+                            "age",
+                            "dateOfBirth",
+                            "createdAt",
+                            "updatedAt"
+                        ],
+                        "type": "string",
+                        "default": "firstName",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "active",
+                            "suspended",
+                            "locked",
+                            "deactivated"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by email (case-insensitive)",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age",
+                        "name": "minAge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age",
+                        "name": "maxAge",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First or last name prefix",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created after this RFC 3339 time",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users created before this RFC 3339 time",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users last changed after this RFC 3339 time",
+                        "name": "updatedAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users last changed before this RFC 3339 time",
+                        "name": "updatedBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Continuation token from a previous page's nextCursor; replaces offset",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "Page of users",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.User"
-                            }
+                            "$ref": "#/definitions/get.UserListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
@@ -75,31 +384,142 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/create.UserRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for validation messages (en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Makes the request safe to retry; the first response is replayed for the same key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as createdBy/updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new user"
+                            }
                         }
                     },
                     "400": {
                         "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "409": {
+                        "description": "Email already in use, or a request with the same Idempotency-Key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Body sent with an Idempotency-Key is too large",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "User violates a data constraint, or the Idempotency-Key was used for a different request",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/events": {
+            "get": {
+                "description": "Stream user changes as server-sent events as they are committed by any instance. Each event's id is the event ID, its event field the event type and its data the event as JSON. Reconnect with Last-Event-ID to receive the events missed since; if that event is no longer remembered, a reset event is sent first and the client should reload the users it shows. Idle streams get a comment every so often to keep them open.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Stream user events",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "user.created",
+                                "user.updated",
+                                "user.status_changed",
+                                "user.deleted",
+                                "user.restored"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only events of these types",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events about this user (UUID)",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume after",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Streaming not supported",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
@@ -125,37 +545,64 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags from previous reads, comma-separated, or *",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "User found",
                         "schema": {
-                            "$ref": "#/definitions/domain.User"
+                            "$ref": "#/definitions/get.UserResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the user"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "User has not changed"
+                    },
                     "400": {
                         "description": "Invalid user ID",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "404": {
                         "description": "User not found",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     }
                 }
             },
             "put": {
-                "description": "Update an existing user by ID",
+                "description": "Update an existing user by ID. The status cannot be set here; use the activate, suspend and deactivate endpoints, which record a reason.",
                 "consumes": [
                     "application/json"
                 ],
@@ -182,6 +629,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/update.UserRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for validation messages (en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as createdBy/updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -189,30 +654,72 @@ const docTemplate = `{
                         "description": "User updated successfully",
                         "schema": {
                             "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
                         }
                     },
                     "400": {
                         "description": "Invalid request / Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Status given, or user violates a data constraint",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                            "$ref": "#/definitions/responses.Problem"
                         }
-                    }
-                }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
             },
             "delete": {
-                "description": "Delete a user by ID",
+                "description": "Soft-delete a user by ID. The user is hidden until restored and purged once the retention period has passed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,6 +737,18 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the delete is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as createdBy/updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -238,43 +757,1738 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    },
-                    "400": {
-                        "description": "Invalid user ID",
-                        "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update a user with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902). The patched user is validated as a whole and written atomically. The status cannot be changed here; use the activate, suspend and deactivate endpoints, which record a reason.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Patch user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch, or a list of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/patch.UserDocument"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language for validation messages (en, es)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the patch is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as createdBy/updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User patched successfully",
+                        "schema": {
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch / Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use, or a test operation failed",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported patch format",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Patch cannot be applied to this user, or changes the status",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activate": {
+            "post": {
+                "description": "Move a pending, suspended, locked or deactivated user to active",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Activate user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the user is being activated",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/status.ChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User activated",
+                        "schema": {
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or missing reason",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The user's current status does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/deactivate": {
+            "post": {
+                "description": "Deactivate a user that is not already deactivated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Deactivate user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the user is being deactivated",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/status.ChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User deactivated",
+                        "schema": {
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or missing reason",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The user's current status does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/history": {
+            "get": {
+                "description": "List every recorded change to a user, oldest first, with the fields each one changed. Deleted users keep their history until they are purged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List user revisions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revisions of the user",
+                        "schema": {
+                            "$ref": "#/definitions/history.UserHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/purge": {
+            "post": {
+                "description": "Permanently remove a user, deleted or not. Requires the admin token.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Purge user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User purged"
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restore": {
+            "post": {
+                "description": "Bring back a soft-deleted user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Restore user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as createdBy/updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User restored successfully",
+                        "schema": {
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "No deleted user with this ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "User is not deleted, or its email is now used by another user",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/snapshot": {
+            "get": {
+                "description": "Rebuild a user from its history as it was at the given revision or time. Exactly one of revision and at is required; age is derived as of that point.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user as of a revision or time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User as it was",
+                        "schema": {
+                            "$ref": "#/definitions/history.SnapshotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User or revision not found, or the user did not exist or was deleted at that time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/suspend": {
+            "post": {
+                "description": "Suspend an active user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Suspend user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the user is being suspended",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/status.ChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is conditional on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who is making the change; recorded as updatedBy",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User suspended",
+                        "schema": {
+                            "$ref": "#/definitions/create.UserResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID or missing reason",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "The user's current status does not allow this",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "User changed since the ETag was issued",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "If-Match header required",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List every webhook subscription, oldest first. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscription.SubscriptionResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to user events. Each delivery is POSTed with X-Webhook-Id, X-Webhook-Event, X-Webhook-Timestamp and an X-Webhook-Signature of \"sha256=\" followed by the hex HMAC-SHA256 of \"timestamp.body\" keyed with the secret. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop sending events to the subscription and discard its deliveries. Requires the admin token.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription deleted"
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "List the events sent or waiting to be sent to a subscription, oldest first. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Only deliveries in this state",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Continue after the delivery with this ID",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.DeliveryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID or query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/attempts": {
+            "get": {
+                "description": "List every attempt made to send a delivery, oldest first. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook delivery attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/subscription.AttemptResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription or delivery ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/replay": {
+            "post": {
+                "description": "Send a delivery again with a fresh set of attempts, whether it was delivered, dead or still pending. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Replay webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Delivery queued",
+                        "schema": {
+                            "$ref": "#/definitions/subscription.DeliveryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription or delivery ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Admin token missing",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Not an admin token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Database unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Database did not respond in time",
+                        "schema": {
+                            "$ref": "#/definitions/responses.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "auditlog.EntryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auditlog.EntryResponse"
+                    }
+                },
+                "nextAfter": {
+                    "type": "integer"
+                }
+            }
+        },
+        "auditlog.EntryResponse": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "occurredAt": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "failure"
+                    ]
+                },
+                "prevHash": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "sourceIp": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "targetUserId": {
+                    "type": "string"
+                }
+            }
+        },
+        "create.UserRequest": {
+            "type": "object",
+            "required": [
+                "dateOfBirth",
+                "email",
+                "firstName",
+                "lastName",
+                "phone"
+            ],
+            "properties": {
+                "dateOfBirth": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "lastName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "active"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.UserStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "create.UserResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "userID": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.UserChanges": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/domain.FieldChange"
+            }
+        },
+        "domain.UserStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "active",
+                "suspended",
+                "locked",
+                "deactivated"
+            ],
+            "x-enum-varnames": [
+                "UserStatusPending",
+                "UserStatusActive",
+                "UserStatusSuspended",
+                "UserStatusLocked",
+                "UserStatusDeactivated"
+            ]
+        },
+        "get.PageLinks": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "self": {
+                    "type": "string"
+                }
+            }
+        },
+        "get.UserListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/get.UserResponseDto"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/get.PageLinks"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "get.UserResponseDto": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstName",
+                "lastName",
+                "phone",
+                "userId"
+            ],
+            "properties": {
+                "age": {
+                    "description": "Age is derived from DateOfBirth and kept for older clients.",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "dateOfBirth": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "lastName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "active",
+                        "suspended",
+                        "locked",
+                        "deactivated"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.UserStatus"
+                        }
+                    ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "up",
+                "degraded",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "StatusUp",
+                "StatusDegraded",
+                "StatusDown"
+            ]
+        },
+        "healthcheck.CheckResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "durationMs": {
+                    "type": "number",
+                    "example": 1.2
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "up",
+                        "degraded",
+                        "down"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Status"
                         }
-                    },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "healthcheck.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/healthcheck.CheckResponse"
+                    }
+                },
+                "draining": {
+                    "description": "Draining is true once the instance has started shutting down.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "enum": [
+                        "up",
+                        "degraded",
+                        "down"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Status"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/responses.Response"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "healthcheck.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "enum": [
+                        "up",
+                        "degraded",
+                        "down"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/health.Status"
                         }
-                    }
+                    ],
+                    "example": "up"
                 }
             }
-        }
-    },
-    "definitions": {
-        "create.UserRequest": {
+        },
+        "history.RevisionResponse": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "changedBy": {
+                    "type": "string"
+                },
+                "changes": {
+                    "$ref": "#/definitions/domain.UserChanges"
+                },
+                "operation": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "deleted",
+                        "restored"
+                    ]
+                },
+                "revision": {
+                    "type": "integer"
+                }
+            }
+        },
+        "history.SnapshotResponse": {
             "type": "object",
             "required": [
-                "age",
                 "email",
                 "firstName",
                 "lastName",
-                "phone"
+                "phone",
+                "userId"
             ],
             "properties": {
                 "age": {
+                    "description": "Age is derived from DateOfBirth and kept for older clients.",
                     "type": "integer"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "dateOfBirth": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -291,80 +2505,263 @@ const docTemplate = `{
                 "phone": {
                     "type": "string"
                 },
+                "revision": {
+                    "type": "integer"
+                },
                 "status": {
                     "enum": [
-                        0,
-                        1
+                        "pending",
+                        "active",
+                        "suspended",
+                        "locked",
+                        "deactivated"
                     ],
                     "allOf": [
                         {
                             "$ref": "#/definitions/domain.UserStatus"
                         }
                     ]
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
                 }
             }
         },
-        "create.UserResponse": {
+        "history.UserHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/history.RevisionResponse"
+                    }
+                }
+            }
+        },
+        "patch.UserDocument": {
             "type": "object",
+            "required": [
+                "dateOfBirth",
+                "email",
+                "firstName",
+                "lastName",
+                "phone",
+                "status"
+            ],
             "properties": {
+                "dateOfBirth": {
+                    "type": "string",
+                    "example": "1990-04-21"
+                },
                 "email": {
                     "type": "string"
                 },
+                "firstName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "lastName": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2
+                },
+                "phone": {
+                    "type": "string"
+                },
                 "status": {
-                    "type": "integer",
-                    "format": "int32"
+                    "enum": [
+                        "pending",
+                        "active",
+                        "suspended",
+                        "locked",
+                        "deactivated"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.UserStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "responses.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
                 },
-                "userID": {
+                "message": {
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
                     "type": "string"
                 }
             }
         },
-        "domain.User": {
+        "responses.Problem": {
             "type": "object",
             "properties": {
-                "age": {
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/responses.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "integer"
                 },
-                "email": {
+                "title": {
                     "type": "string"
                 },
-                "firstName": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "status.ChangeRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "subscription.AttemptResponse": {
+            "type": "object",
+            "properties": {
+                "attemptedAt": {
                     "type": "string"
                 },
-                "lastName": {
+                "durationMs": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "phone": {
+                "statusCode": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscription.DeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/subscription.DeliveryResponse"
+                    }
+                },
+                "nextAfter": {
+                    "type": "integer"
+                }
+            }
+        },
+        "subscription.DeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/domain.UserStatus"
+                "deliveredAt": {
+                    "type": "string"
                 },
-                "userId": {
+                "eventId": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "nextAttemptAt": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "dead"
+                    ]
                 }
             }
         },
-        "domain.UserStatus": {
-            "type": "integer",
-            "enum": [
-                0,
-                1,
-                2
+        "subscription.SubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "eventTypes",
+                "secret",
+                "url"
             ],
-            "x-enum-varnames": [
-                "UserStatusDefault",
-                "UserStatusActive",
-                "UserStatusInactive"
-            ]
+            "properties": {
+                "eventTypes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "user.created",
+                        "user.deleted"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret keys the HMAC-SHA256 signature sent with every delivery. It is\nnever returned.",
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/hooks/users"
+                }
+            }
         },
-        "responses.Response": {
+        "subscription.SubscriptionResponse": {
             "type": "object",
             "properties": {
-                "errors": {
+                "createdAt": {
                     "type": "string"
                 },
-                "message": {
+                "eventTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
@@ -372,10 +2769,9 @@ const docTemplate = `{
         "update.UserRequest": {
             "type": "object",
             "properties": {
-                "age": {
-                    "type": "integer",
-                    "maximum": 150,
-                    "minimum": 0
+                "dateOfBirth": {
+                    "type": "string",
+                    "example": "1990-04-21"
                 },
                 "email": {
                     "type": "string"
@@ -392,9 +2788,6 @@ const docTemplate = `{
                 },
                 "phone": {
                     "type": "string"
-                },
-                "status": {
-                    "type": "integer"
                 }
             }
         }
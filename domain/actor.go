@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes writes made with it to actor,
+// which the repository records in the created_by and updated_by columns.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor writes made with ctx are attributed to, or ""
+// when it is unknown.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "errors"
+
+type ErrorKind int
+
+const (
+	ErrorKindInternal ErrorKind = iota
+	ErrorKindNotFound
+	ErrorKindConflict
+	ErrorKindValidation
+	ErrorKindUnavailable
+	ErrorKindPreconditionFailed
+	// ErrorKindTimeout means the work did not finish within its deadline.
+	ErrorKindTimeout
+	// ErrorKindCanceled means the caller gave up, such as a client that
+	// disconnected, so nothing failed on our side.
+	ErrorKindCanceled
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindNotFound:
+		return "not_found"
+	case ErrorKindConflict:
+		return "conflict"
+	case ErrorKindValidation:
+		return "validation"
+	case ErrorKindUnavailable:
+		return "unavailable"
+	case ErrorKindPreconditionFailed:
+		return "precondition_failed"
+	case ErrorKindTimeout:
+		return "timeout"
+	case ErrorKindCanceled:
+		return "canceled"
+	default:
+		return "internal"
+	}
+}
+
+// Error is the error type returned by repositories. Message is safe to show
+// to API clients; Err keeps the underlying cause for logs.
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewNotFoundError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindNotFound, Message: message, Err: err}
+}
+
+func NewConflictError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindConflict, Message: message, Err: err}
+}
+
+func NewValidationError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindValidation, Message: message, Err: err}
+}
+
+func NewUnavailableError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindUnavailable, Message: message, Err: err}
+}
+
+func NewPreconditionFailedError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindPreconditionFailed, Message: message, Err: err}
+}
+
+func NewTimeoutError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindTimeout, Message: message, Err: err}
+}
+
+func NewCanceledError(message string, err error) *Error {
+	return &Error{Kind: ErrorKindCanceled, Message: message, Err: err}
+}
+
+// ErrorKindOf reports the kind of the first domain Error in err's chain, or
+// ErrorKindInternal when there is none.
+func ErrorKindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return ErrorKindInternal
+}
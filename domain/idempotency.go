@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotentResponse is the response stored for an idempotency key. A zero
+// StatusCode means the original request is still being processed.
+type IdempotentResponse struct {
+	RequestHash string
+	StatusCode  int
+	Header      map[string][]string
+	Body        []byte
+}
+
+type IdempotencyRepository interface {
+	// Claim reserves key for the request identified by requestHash for ttl,
+	// holding it for lease while the request is processed, and returns a
+	// token identifying this claim. When the key is already held by an
+	// unexpired request it returns uuid.Nil together with whatever that
+	// request stored. A claim that has neither completed nor been released
+	// by the end of its lease, such as one left by a crashed process, is
+	// taken over.
+	Claim(c context.Context, key string, requestHash string, ttl time.Duration, lease time.Duration) (uuid.UUID, IdempotentResponse, error)
+	// Complete stores the response produced under claim. It fails with a
+	// conflict once another request has taken the key over.
+	Complete(c context.Context, key string, claim uuid.UUID, response IdempotentResponse) error
+	// Release gives up claim without a response so the key can be retried.
+	// It does nothing once another request has taken the key over.
+	Release(c context.Context, key string, claim uuid.UUID) error
+	// DeleteExpired removes keys whose TTL has passed.
+	DeleteExpired(c context.Context) (int64, error)
+}
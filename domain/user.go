@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 	"user-management/internal/db"
 
 	"github.com/google/uuid"
@@ -13,22 +14,67 @@ type User struct {
 	LastName  string
 	Email     string
 	Phone     string
-	Age       int
-	Status    UserStatus
+	// DateOfBirth is stored; Age is derived from it whenever a user is read
+	// and ignored on writes.
+	DateOfBirth time.Time
+	Age         int
+	Status      UserStatus
+	// Version is bumped on every write. When passed to Update it is the
+	// version the caller expects to overwrite; 0 skips the check.
+	Version int
+	// CreatedAt, UpdatedAt and the actors behind them are maintained by the
+	// repository and ignored when passed in.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy string
+	UpdatedBy string
 }
 
-type UserStatus int
+// AgeOn returns the age in whole years on day of someone born on
+// dateOfBirth.
+func AgeOn(dateOfBirth time.Time, day time.Time) int {
+	age := day.Year() - dateOfBirth.Year()
 
-const (
-	UserStatusDefault UserStatus = iota
-	UserStatusActive
-	UserStatusInactive
-)
+	birthdayMonth, birthdayDay := dateOfBirth.Month(), dateOfBirth.Day()
+	if day.Month() < birthdayMonth || (day.Month() == birthdayMonth && day.Day() < birthdayDay) {
+		age--
+	}
+
+	return age
+}
 
 type UserRepository interface {
 	Create(ctx context.Context, user *User) (db.CreateUserRow, error)
-	GetAll(c context.Context) ([]User, error)
+	GetAll(c context.Context, query UserQuery) ([]User, int64, error)
+	GetAllAfter(c context.Context, query UserQuery, after UserCursor) ([]User, error)
 	GetById(c context.Context, id uuid.UUID) (User, error)
 	Update(c context.Context, id uuid.UUID, user *User) (db.UpdateUserRow, error)
-	Delete(c context.Context, id uuid.UUID) (uuid.UUID, error)
+	// Replace overwrites every field of the user, including empty ones, if
+	// its version is still user.Version.
+	Replace(c context.Context, user *User) (db.UpdateUserRow, error)
+	// ChangeStatus moves the user to status, recording reason, if the
+	// lifecycle allows it and its version is expectedVersion (0 skips the
+	// version check).
+	ChangeStatus(c context.Context, id uuid.UUID, status UserStatus, reason string, expectedVersion int) (db.UpdateUserRow, error)
+	// Delete soft-deletes the user if its version is expectedVersion, or
+	// unconditionally when expectedVersion is 0. Deleted users are hidden from
+	// every other method until they are restored.
+	Delete(c context.Context, id uuid.UUID, expectedVersion int) (uuid.UUID, error)
+	// Restore brings back a soft-deleted user.
+	Restore(c context.Context, id uuid.UUID) (db.UpdateUserRow, error)
+	// Purge permanently removes the user, whether or not it was deleted.
+	Purge(c context.Context, id uuid.UUID) error
+	// GetHistory returns every recorded revision of the user, oldest first,
+	// including those of a soft-deleted user.
+	GetHistory(c context.Context, id uuid.UUID) ([]UserRevision, error)
+	// GetRevision returns the user as it was at the given revision.
+	GetRevision(c context.Context, id uuid.UUID, revision int) (User, error)
+	// GetAsOf returns the user as it was at the given time.
+	GetAsOf(c context.Context, id uuid.UUID, at time.Time) (User, error)
+	// PurgeDeleted permanently removes users soft-deleted before the given
+	// time and reports how many there were.
+	PurgeDeleted(c context.Context, before time.Time) (int64, error)
+	// CountByStatus counts the users that are not deleted in each status.
+	// Statuses without users are left out.
+	CountByStatus(c context.Context) (map[UserStatus]int64, error)
 }
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserEventType string
+
+const (
+	UserEventCreated       UserEventType = "user.created"
+	UserEventUpdated       UserEventType = "user.updated"
+	UserEventStatusChanged UserEventType = "user.status_changed"
+	UserEventDeleted       UserEventType = "user.deleted"
+	UserEventRestored      UserEventType = "user.restored"
+)
+
+// UserEvent tells other services about a change to a user. Events may be
+// delivered more than once and, when several instances dispatch them, out of
+// order; Version lets consumers discard stale ones.
+type UserEvent struct {
+	ID         uuid.UUID     `json:"id"`
+	Type       UserEventType `json:"type"`
+	UserId     uuid.UUID     `json:"userId"`
+	Version    int           `json:"version"`
+	Actor      string        `json:"actor,omitempty"`
+	OccurredAt time.Time     `json:"occurredAt"`
+	Changes    UserChanges   `json:"changes,omitempty"`
+	// Reason is given for status changes made through the lifecycle
+	// actions.
+	Reason string `json:"reason,omitempty"`
+}
+
+// NewUserEvents returns the events describing a write that took a user from
+// before to after. An update that only moves the status is reported as a
+// status change alone; one that also edits other fields produces both.
+func NewUserEvents(op UserOperation, before, after User, reason string) []UserEvent {
+	changes := DiffUsers(before, after)
+
+	event := func(eventType UserEventType, changes UserChanges) UserEvent {
+		return UserEvent{
+			ID:         uuid.New(),
+			Type:       eventType,
+			UserId:     after.UserId,
+			Version:    after.Version,
+			Actor:      after.UpdatedBy,
+			OccurredAt: after.UpdatedAt,
+			Changes:    changes,
+		}
+	}
+
+	switch op {
+	case UserCreated:
+		return []UserEvent{event(UserEventCreated, changes)}
+	case UserDeleted:
+		return []UserEvent{event(UserEventDeleted, nil)}
+	case UserRestored:
+		return []UserEvent{event(UserEventRestored, nil)}
+	}
+
+	statusChange, statusMoved := changes["status"]
+	delete(changes, "status")
+
+	var events []UserEvent
+	if len(changes) > 0 || !statusMoved {
+		events = append(events, event(UserEventUpdated, changes))
+	}
+
+	if statusMoved {
+		statusEvent := event(UserEventStatusChanged, UserChanges{"status": statusChange})
+		statusEvent.Reason = reason
+		events = append(events, statusEvent)
+	}
+
+	return events
+}
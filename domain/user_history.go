@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserOperation is the kind of write that produced a revision.
+type UserOperation string
+
+const (
+	UserCreated  UserOperation = "created"
+	UserUpdated  UserOperation = "updated"
+	UserDeleted  UserOperation = "deleted"
+	UserRestored UserOperation = "restored"
+)
+
+// FieldChange holds a field's value before and after a revision. From is
+// empty for revisions that created the user.
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// UserChanges maps the API name of every field a revision changed to how it
+// changed.
+type UserChanges map[string]FieldChange
+
+// UserRevision is one recorded write to a user. Revision is the user's
+// version after the write.
+type UserRevision struct {
+	UserId    uuid.UUID
+	Revision  int
+	Operation UserOperation
+	Changes   UserChanges
+	ChangedBy string
+	ChangedAt time.Time
+}
+
+// userFields returns the fields of u kept in its history, keyed by their API
+// names.
+func userFields(u User) map[string]string {
+	dateOfBirth := ""
+	if !u.DateOfBirth.IsZero() {
+		dateOfBirth = u.DateOfBirth.Format(time.DateOnly)
+	}
+
+	return map[string]string{
+		"firstName":   u.FirstName,
+		"lastName":    u.LastName,
+		"email":       u.Email,
+		"phone":       u.Phone,
+		"dateOfBirth": dateOfBirth,
+		"status":      string(u.Status),
+	}
+}
+
+// DiffUsers returns the fields that differ between before and after. Diffing
+// against the zero User lists every field that is set.
+func DiffUsers(before, after User) UserChanges {
+	from, to := userFields(before), userFields(after)
+
+	changes := UserChanges{}
+	for field, value := range to {
+		if from[field] != value {
+			changes[field] = FieldChange{From: from[field], To: value}
+		}
+	}
+
+	return changes
+}
+
+// ReplayUser rebuilds a user by applying its revisions, oldest first, and
+// reports whether the last of them left it deleted. Age is left for the caller
+// to derive as of whatever time it is interested in.
+func ReplayUser(revisions []UserRevision) (user User, deleted bool) {
+	for i, revision := range revisions {
+		for field, change := range revision.Changes {
+			setUserField(&user, field, change.To)
+		}
+
+		if i == 0 {
+			user.UserId = revision.UserId
+			user.CreatedAt = revision.ChangedAt
+			user.CreatedBy = revision.ChangedBy
+		}
+		user.Version = revision.Revision
+		user.UpdatedAt = revision.ChangedAt
+		user.UpdatedBy = revision.ChangedBy
+
+		switch revision.Operation {
+		case UserDeleted:
+			deleted = true
+		case UserRestored:
+			deleted = false
+		}
+	}
+
+	return user, deleted
+}
+
+func setUserField(u *User, field, value string) {
+	switch field {
+	case "firstName":
+		u.FirstName = value
+	case "lastName":
+		u.LastName = value
+	case "email":
+		u.Email = value
+	case "phone":
+		u.Phone = value
+	case "dateOfBirth":
+		u.DateOfBirth, _ = time.Parse(time.DateOnly, value)
+	case "status":
+		u.Status = UserStatus(value)
+	}
+}
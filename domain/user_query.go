@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserSortField string
+
+const (
+	UserSortByFirstName   UserSortField = "firstName"
+	UserSortByLastName    UserSortField = "lastName"
+	UserSortByEmail       UserSortField = "email"
+	UserSortByAge         UserSortField = "age"
+	UserSortByDateOfBirth UserSortField = "dateOfBirth"
+	UserSortByCreatedAt   UserSortField = "createdAt"
+	UserSortByUpdatedAt   UserSortField = "updatedAt"
+)
+
+// UserFilter narrows a user listing. Nil and empty fields are not applied.
+// The time bounds are exclusive.
+type UserFilter struct {
+	Status        *UserStatus
+	Email         string
+	MinAge        *int
+	MaxAge        *int
+	NamePrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+}
+
+// UserQuery describes one page of a filtered and sorted user listing. Offset
+// is ignored when the page is fetched after a cursor.
+type UserQuery struct {
+	Filter   UserFilter
+	SortBy   UserSortField
+	SortDesc bool
+	Limit    int
+	Offset   int
+}
+
+// UserCursor identifies the last user of a keyset page: the value of the sort
+// column and the user ID that breaks ties on it.
+type UserCursor struct {
+	SortBy     UserSortField
+	SortDesc   bool
+	LastValue  string
+	LastUserId uuid.UUID
+}
+
+// NewUserCursor returns the cursor positioned just after user for the given
+// sort order.
+func NewUserCursor(user User, sortBy UserSortField, sortDesc bool) UserCursor {
+	cursor := UserCursor{
+		SortBy:     sortBy,
+		SortDesc:   sortDesc,
+		LastUserId: user.UserId,
+	}
+
+	switch sortBy {
+	case UserSortByLastName:
+		cursor.LastValue = user.LastName
+	case UserSortByEmail:
+		cursor.LastValue = user.Email
+	case UserSortByAge, UserSortByDateOfBirth:
+		cursor.LastValue = user.DateOfBirth.Format(time.DateOnly)
+	case UserSortByCreatedAt:
+		cursor.LastValue = user.CreatedAt.Format(time.RFC3339Nano)
+	case UserSortByUpdatedAt:
+		cursor.LastValue = user.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.LastValue = user.FirstName
+	}
+
+	return cursor
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "fmt"
+
+type UserStatus string
+
+const (
+	UserStatusPending     UserStatus = "pending"
+	UserStatusActive      UserStatus = "active"
+	UserStatusSuspended   UserStatus = "suspended"
+	UserStatusLocked      UserStatus = "locked"
+	UserStatusDeactivated UserStatus = "deactivated"
+)
+
+// UserStatuses lists every status in lifecycle order.
+func UserStatuses() []UserStatus {
+	return []UserStatus{
+		UserStatusPending,
+		UserStatusActive,
+		UserStatusSuspended,
+		UserStatusLocked,
+		UserStatusDeactivated,
+	}
+}
+
+// userStatusTransitions lists the statuses a user may move to from each
+// status. Deactivated users can only be brought back by activating them.
+var userStatusTransitions = map[UserStatus][]UserStatus{
+	UserStatusPending:     {UserStatusActive, UserStatusDeactivated},
+	UserStatusActive:      {UserStatusSuspended, UserStatusLocked, UserStatusDeactivated},
+	UserStatusSuspended:   {UserStatusActive, UserStatusDeactivated},
+	UserStatusLocked:      {UserStatusActive, UserStatusDeactivated},
+	UserStatusDeactivated: {UserStatusActive},
+}
+
+// IsValid reports whether s is one of the known statuses.
+func (s UserStatus) IsValid() bool {
+	_, ok := userStatusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a user may move from s to next.
+func (s UserStatus) CanTransitionTo(next UserStatus) bool {
+	for _, allowed := range userStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns a conflict error when a user may not move from s
+// to next. Staying in the same status is always allowed.
+func (s UserStatus) CheckTransition(next UserStatus) error {
+	if s == next || s.CanTransitionTo(next) {
+		return nil
+	}
+	return NewConflictError(fmt.Sprintf("user cannot go from %s to %s", s, next), nil)
+}
@@ -0,0 +1,70 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidToken = errors.New("invalid continuation token")
+
+// Codec turns user cursors into opaque continuation tokens and back. Tokens
+// are signed with HMAC-SHA256 so a client cannot forge or edit a position.
+type Codec struct {
+	secret []byte
+}
+
+type payload struct {
+	SortBy     domain.UserSortField `json:"s"`
+	SortDesc   bool                 `json:"d,omitempty"`
+	LastValue  string               `json:"v"`
+	LastUserId uuid.UUID            `json:"id"`
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+func (c *Codec) Encode(cursor domain.UserCursor) string {
+	body, _ := json.Marshal(payload(cursor))
+
+	encodedBody := base64.RawURLEncoding.EncodeToString(body)
+
+	return encodedBody + "." + base64.RawURLEncoding.EncodeToString(c.sign(encodedBody))
+}
+
+func (c *Codec) Decode(token string) (domain.UserCursor, error) {
+	encodedBody, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return domain.UserCursor{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(encodedBody)) {
+		return domain.UserCursor{}, ErrInvalidToken
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encodedBody)
+	if err != nil {
+		return domain.UserCursor{}, ErrInvalidToken
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return domain.UserCursor{}, ErrInvalidToken
+	}
+
+	return domain.UserCursor(decoded), nil
+}
+
+func (c *Codec) sign(encodedBody string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(encodedBody))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,148 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: audit_log.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuditEntry = `-- name: CreateAuditEntry :one
+INSERT INTO audit_log (
+    occurred_at,
+    actor,
+    source_ip,
+    request_id,
+    method,
+    route,
+    target_user_id,
+    status,
+    outcome,
+    prev_hash,
+    hash
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    RETURNING id
+`
+
+type CreateAuditEntryParams struct {
+	OccurredAt   pgtype.Timestamptz
+	Actor        string
+	SourceIp     string
+	RequestID    string
+	Method       string
+	Route        string
+	TargetUserID pgtype.UUID
+	Status       int32
+	Outcome      string
+	PrevHash     string
+	Hash         string
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (int64, error) {
+	row := q.db.QueryRow(ctx, createAuditEntry,
+		arg.OccurredAt,
+		arg.Actor,
+		arg.SourceIp,
+		arg.RequestID,
+		arg.Method,
+		arg.Route,
+		arg.TargetUserID,
+		arg.Status,
+		arg.Outcome,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getLastAuditHash = `-- name: GetLastAuditHash :one
+SELECT hash FROM audit_log
+ORDER BY id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastAuditHash(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, getLastAuditHash)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, occurred_at, actor, source_ip, request_id, method, route, target_user_id, status, outcome, prev_hash, hash FROM audit_log
+WHERE id > $1
+  AND ($2::text IS NULL OR actor = $2)
+  AND ($3::uuid IS NULL OR target_user_id = $3)
+  AND ($4::text IS NULL OR outcome = $4)
+  AND ($5::timestamptz IS NULL OR occurred_at > $5)
+  AND ($6::timestamptz IS NULL OR occurred_at < $6)
+ORDER BY id
+LIMIT $7
+`
+
+type ListAuditEntriesParams struct {
+	AfterID        int64
+	Actor          pgtype.Text
+	TargetUserID   pgtype.UUID
+	Outcome        pgtype.Text
+	OccurredAfter  pgtype.Timestamptz
+	OccurredBefore pgtype.Timestamptz
+	PageLimit      int32
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, listAuditEntries,
+		arg.AfterID,
+		arg.Actor,
+		arg.TargetUserID,
+		arg.Outcome,
+		arg.OccurredAfter,
+		arg.OccurredBefore,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccurredAt,
+			&i.Actor,
+			&i.SourceIp,
+			&i.RequestID,
+			&i.Method,
+			&i.Route,
+			&i.TargetUserID,
+			&i.Status,
+			&i.Outcome,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAuditLog = `-- name: LockAuditLog :exec
+LOCK TABLE audit_log IN SHARE ROW EXCLUSIVE MODE
+`
+
+func (q *Queries) LockAuditLog(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockAuditLog)
+	return err
+}
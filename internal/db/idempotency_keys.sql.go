@@ -0,0 +1,148 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: idempotency_keys.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    idempotency_key,
+    request_hash,
+    expires_at,
+    locked_until,
+    claim_token
+)
+VALUES (
+    $1,
+    $2,
+    now() + make_interval(secs => $3::int),
+    now() + make_interval(secs => $4::int),
+    $5
+)
+ON CONFLICT (idempotency_key) DO UPDATE
+SET
+    request_hash     = EXCLUDED.request_hash,
+    status_code      = NULL,
+    response_headers = NULL,
+    response_body    = NULL,
+    created_at       = now(),
+    expires_at       = EXCLUDED.expires_at,
+    locked_until     = EXCLUDED.locked_until,
+    claim_token      = EXCLUDED.claim_token
+WHERE idempotency_keys.expires_at <= now()
+   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= now())
+    RETURNING idempotency_key
+`
+
+type ClaimIdempotencyKeyParams struct {
+	IdempotencyKey string
+	RequestHash    string
+	TtlSeconds     int32
+	LeaseSeconds   int32
+	ClaimToken     pgtype.UUID
+}
+
+// Takes over keys that have expired, and unfinished claims whose lease has
+// run out.
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (string, error) {
+	row := q.db.QueryRow(ctx, claimIdempotencyKey,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+		arg.TtlSeconds,
+		arg.LeaseSeconds,
+		arg.ClaimToken,
+	)
+	var idempotency_key string
+	err := row.Scan(&idempotency_key)
+	return idempotency_key, err
+}
+
+const completeIdempotencyKey = `-- name: CompleteIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET
+    status_code      = $1,
+    response_headers = $2,
+    response_body    = $3
+WHERE idempotency_key = $4
+  AND claim_token = $5
+  AND status_code IS NULL
+`
+
+type CompleteIdempotencyKeyParams struct {
+	StatusCode      pgtype.Int4
+	ResponseHeaders []byte
+	ResponseBody    []byte
+	IdempotencyKey  string
+	ClaimToken      pgtype.UUID
+}
+
+func (q *Queries) CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, completeIdempotencyKey,
+		arg.StatusCode,
+		arg.ResponseHeaders,
+		arg.ResponseBody,
+		arg.IdempotencyKey,
+		arg.ClaimToken,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredIdempotencyKeys = `-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at <= now()
+`
+
+func (q *Queries) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredIdempotencyKeys)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT idempotency_key, request_hash, status_code, response_headers, response_body, created_at, expires_at, locked_until, claim_token FROM idempotency_keys WHERE idempotency_key = $1 LIMIT 1
+`
+
+func (q *Queries) GetIdempotencyKey(ctx context.Context, idempotencyKey string) (IdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, idempotencyKey)
+	var i IdempotencyKey
+	err := row.Scan(
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.StatusCode,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LockedUntil,
+		&i.ClaimToken,
+	)
+	return i, err
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE idempotency_key = $1
+  AND claim_token = $2
+  AND status_code IS NULL
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	IdempotencyKey string
+	ClaimToken     pgtype.UUID
+}
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, arg.IdempotencyKey, arg.ClaimToken)
+	return err
+}
@@ -8,12 +8,110 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditLog struct {
+	ID           int64
+	OccurredAt   pgtype.Timestamptz
+	Actor        string
+	SourceIp     string
+	RequestID    string
+	Method       string
+	Route        string
+	TargetUserID pgtype.UUID
+	Status       int32
+	Outcome      string
+	PrevHash     string
+	Hash         string
+}
+
+type IdempotencyKey struct {
+	IdempotencyKey  string
+	RequestHash     string
+	StatusCode      pgtype.Int4
+	ResponseHeaders []byte
+	ResponseBody    []byte
+	CreatedAt       pgtype.Timestamptz
+	ExpiresAt       pgtype.Timestamptz
+	LockedUntil     pgtype.Timestamptz
+	ClaimToken      pgtype.UUID
+}
+
+type Outbox struct {
+	ID          int64
+	EventID     pgtype.UUID
+	EventType   string
+	AggregateID pgtype.UUID
+	Payload     []byte
+	CreatedAt   pgtype.Timestamptz
+	DeliveredAt pgtype.Timestamptz
+	Attempts    int32
+	LastError   pgtype.Text
+	Status      string
+	LockedUntil pgtype.Timestamptz
+}
+
 type User struct {
+	UserID      pgtype.UUID
+	FirstName   string
+	LastName    string
+	Email       string
+	Phone       string
+	Status      string
+	Version     int32
+	DeletedAt   pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	CreatedBy   pgtype.Text
+	UpdatedBy   pgtype.Text
+	DateOfBirth pgtype.Date
+}
+
+type UserHistory struct {
+	ID        int64
 	UserID    pgtype.UUID
-	FirstName string
-	LastName  string
-	Email     string
-	Phone     string
-	Age       int32
-	Status    int32
+	Revision  int32
+	Operation string
+	Changes   []byte
+	ChangedBy pgtype.Text
+	ChangedAt pgtype.Timestamptz
+}
+
+type UserStatusChange struct {
+	ID         int64
+	UserID     pgtype.UUID
+	FromStatus string
+	ToStatus   string
+	Reason     pgtype.Text
+	ChangedBy  pgtype.Text
+	ChangedAt  pgtype.Timestamptz
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID pgtype.UUID
+	EventID        pgtype.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int32
+	NextAttemptAt  pgtype.Timestamptz
+	LastError      pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	DeliveredAt    pgtype.Timestamptz
+}
+
+type WebhookDeliveryAttempt struct {
+	ID          int64
+	DeliveryID  int64
+	AttemptedAt pgtype.Timestamptz
+	StatusCode  pgtype.Int4
+	Error       pgtype.Text
+	DurationMs  int32
+}
+
+type WebhookSubscription struct {
+	ID         pgtype.UUID
+	Url        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  pgtype.Timestamptz
 }
@@ -0,0 +1,150 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+UPDATE outbox
+SET locked_until = now() + make_interval(secs => $1::float8)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE status = 'pending' AND (locked_until IS NULL OR locked_until <= now())
+    ORDER BY id
+    LIMIT $2
+        FOR UPDATE SKIP LOCKED
+)
+    RETURNING id, event_id, event_type, aggregate_id, payload, created_at, delivered_at, attempts, last_error, status, locked_until
+`
+
+type ClaimOutboxEventsParams struct {
+	LeaseSeconds float64
+	BatchSize    int32
+}
+
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, arg ClaimOutboxEventsParams) ([]Outbox, error) {
+	rows, err := q.db.Query(ctx, claimOutboxEvents, arg.LeaseSeconds, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventID,
+			&i.EventType,
+			&i.AggregateID,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.Status,
+			&i.LockedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOutboxEvent = `-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (
+    event_id,
+    event_type,
+    aggregate_id,
+    payload
+)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateOutboxEventParams struct {
+	EventID     pgtype.UUID
+	EventType   string
+	AggregateID pgtype.UUID
+	Payload     []byte
+}
+
+func (q *Queries) CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) error {
+	_, err := q.db.Exec(ctx, createOutboxEvent,
+		arg.EventID,
+		arg.EventType,
+		arg.AggregateID,
+		arg.Payload,
+	)
+	return err
+}
+
+const deleteDeliveredOutboxEvents = `-- name: DeleteDeliveredOutboxEvents :execrows
+DELETE FROM outbox
+WHERE status = 'delivered'
+  AND delivered_at < now() - make_interval(secs => $1::float8)
+`
+
+func (q *Queries) DeleteDeliveredOutboxEvents(ctx context.Context, retentionSeconds float64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDeliveredOutboxEvents, retentionSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markOutboxEventDelivered = `-- name: MarkOutboxEventDelivered :exec
+UPDATE outbox
+SET
+    status       = 'delivered',
+    delivered_at = now(),
+    attempts     = attempts + 1,
+    locked_until = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxEventDelivered(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markOutboxEventDelivered, id)
+	return err
+}
+
+const recordOutboxEventFailure = `-- name: RecordOutboxEventFailure :exec
+UPDATE outbox
+SET
+    status       = $1,
+    attempts     = attempts + 1,
+    last_error   = $2,
+    locked_until = NULL
+WHERE id = $3
+`
+
+type RecordOutboxEventFailureParams struct {
+	Status    string
+	LastError pgtype.Text
+	ID        int64
+}
+
+func (q *Queries) RecordOutboxEventFailure(ctx context.Context, arg RecordOutboxEventFailureParams) error {
+	_, err := q.db.Exec(ctx, recordOutboxEventFailure, arg.Status, arg.LastError, arg.ID)
+	return err
+}
+
+const releaseOutboxEvents = `-- name: ReleaseOutboxEvents :exec
+UPDATE outbox
+SET locked_until = NULL
+WHERE id = ANY ($1::bigint[])
+`
+
+func (q *Queries) ReleaseOutboxEvents(ctx context.Context, ids []int64) error {
+	_, err := q.db.Exec(ctx, releaseOutboxEvents, ids)
+	return err
+}
@@ -0,0 +1,21 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_events.sql
+
+package db
+
+import (
+	"context"
+)
+
+const notifyUserEvent = `-- name: NotifyUserEvent :exec
+SELECT pg_notify('user_events', $1::text)
+`
+
+// Listeners on the user_events channel receive the payload when the
+// transaction commits, and never if it rolls back.
+func (q *Queries) NotifyUserEvent(ctx context.Context, payload string) error {
+	_, err := q.db.Exec(ctx, notifyUserEvent, payload)
+	return err
+}
@@ -0,0 +1,118 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_history.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createUserRevision = `-- name: CreateUserRevision :exec
+INSERT INTO user_history (
+    user_id,
+    revision,
+    operation,
+    changes,
+    changed_by
+)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateUserRevisionParams struct {
+	UserID    pgtype.UUID
+	Revision  int32
+	Operation string
+	Changes   []byte
+	ChangedBy pgtype.Text
+}
+
+func (q *Queries) CreateUserRevision(ctx context.Context, arg CreateUserRevisionParams) error {
+	_, err := q.db.Exec(ctx, createUserRevision,
+		arg.UserID,
+		arg.Revision,
+		arg.Operation,
+		arg.Changes,
+		arg.ChangedBy,
+	)
+	return err
+}
+
+const listUserRevisions = `-- name: ListUserRevisions :many
+SELECT id, user_id, revision, operation, changes, changed_by, changed_at FROM user_history
+WHERE user_id = $1
+ORDER BY revision
+`
+
+func (q *Queries) ListUserRevisions(ctx context.Context, userID pgtype.UUID) ([]UserHistory, error) {
+	rows, err := q.db.Query(ctx, listUserRevisions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserHistory
+	for rows.Next() {
+		var i UserHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Revision,
+			&i.Operation,
+			&i.Changes,
+			&i.ChangedBy,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRevisionsUpTo = `-- name: ListUserRevisionsUpTo :many
+SELECT id, user_id, revision, operation, changes, changed_by, changed_at FROM user_history
+WHERE user_id = $1
+  AND ($2::int IS NULL OR revision <= $2)
+  AND ($3::timestamptz IS NULL OR changed_at <= $3)
+ORDER BY revision
+`
+
+type ListUserRevisionsUpToParams struct {
+	UserID    pgtype.UUID
+	Revision  pgtype.Int4
+	ChangedAt pgtype.Timestamptz
+}
+
+func (q *Queries) ListUserRevisionsUpTo(ctx context.Context, arg ListUserRevisionsUpToParams) ([]UserHistory, error) {
+	rows, err := q.db.Query(ctx, listUserRevisionsUpTo, arg.UserID, arg.Revision, arg.ChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserHistory
+	for rows.Next() {
+		var i UserHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Revision,
+			&i.Operation,
+			&i.Changes,
+			&i.ChangedBy,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
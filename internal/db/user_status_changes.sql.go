@@ -0,0 +1,42 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: user_status_changes.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createUserStatusChange = `-- name: CreateUserStatusChange :exec
+INSERT INTO user_status_changes (
+    user_id,
+    from_status,
+    to_status,
+    reason,
+    changed_by
+)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateUserStatusChangeParams struct {
+	UserID     pgtype.UUID
+	FromStatus string
+	ToStatus   string
+	Reason     pgtype.Text
+	ChangedBy  pgtype.Text
+}
+
+func (q *Queries) CreateUserStatusChange(ctx context.Context, arg CreateUserStatusChangeParams) error {
+	_, err := q.db.Exec(ctx, createUserStatusChange,
+		arg.UserID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.ChangedBy,
+	)
+	return err
+}
@@ -11,6 +11,84 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countUsers = `-- name: CountUsers :one
+SELECT count(*) FROM users
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR status = $1)
+  AND ($2::text IS NULL OR lower(email) = lower($2))
+  AND ($3::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => $3::int))::date)
+  AND ($4::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => $4::int + 1))::date)
+  AND ($5::text IS NULL
+    OR lower(first_name) LIKE $5
+    OR lower(last_name) LIKE $5)
+  AND ($6::timestamptz IS NULL OR created_at > $6)
+  AND ($7::timestamptz IS NULL OR created_at < $7)
+  AND ($8::timestamptz IS NULL OR updated_at > $8)
+  AND ($9::timestamptz IS NULL OR updated_at < $9)
+`
+
+type CountUsersParams struct {
+	Status        pgtype.Text
+	Email         pgtype.Text
+	MinAge        pgtype.Int4
+	MaxAge        pgtype.Int4
+	NamePrefix    pgtype.Text
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	UpdatedAfter  pgtype.Timestamptz
+	UpdatedBefore pgtype.Timestamptz
+}
+
+func (q *Queries) CountUsers(ctx context.Context, arg CountUsersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers,
+		arg.Status,
+		arg.Email,
+		arg.MinAge,
+		arg.MaxAge,
+		arg.NamePrefix,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsersByStatus = `-- name: CountUsersByStatus :many
+SELECT status, count(*) AS users FROM users
+WHERE deleted_at IS NULL
+GROUP BY status
+`
+
+type CountUsersByStatusRow struct {
+	Status string
+	Users  int64
+}
+
+func (q *Queries) CountUsersByStatus(ctx context.Context) ([]CountUsersByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countUsersByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUsersByStatusRow
+	for rows.Next() {
+		var i CountUsersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Users); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     user_id,
@@ -18,27 +96,35 @@ INSERT INTO users (
     last_name,
     email,
     phone,
-    age,
-    status
+    date_of_birth,
+    status,
+    created_by,
+    updated_by
 )
-VALUES ( $1, $2, $3, $4, $5, $6, $7)
-    RETURNING user_id, email, status
+VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $8)
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by
 `
 
 type CreateUserParams struct {
-	UserID    pgtype.UUID
-	FirstName string
-	LastName  string
-	Email     string
-	Phone     string
-	Age       int32
-	Status    int32
+	UserID      pgtype.UUID
+	FirstName   string
+	LastName    string
+	Email       string
+	Phone       string
+	DateOfBirth pgtype.Date
+	Status      string
+	CreatedBy   pgtype.Text
 }
 
 type CreateUserRow struct {
-	UserID pgtype.UUID
-	Email  string
-	Status int32
+	UserID    pgtype.UUID
+	Email     string
+	Status    string
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	CreatedBy pgtype.Text
+	UpdatedBy pgtype.Text
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
@@ -48,33 +134,168 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateU
 		arg.LastName,
 		arg.Email,
 		arg.Phone,
-		arg.Age,
+		arg.DateOfBirth,
 		arg.Status,
+		arg.CreatedBy,
 	)
 	var i CreateUserRow
-	err := row.Scan(&i.UserID, &i.Email, &i.Status)
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
 	return i, err
 }
 
 const deleteUser = `-- name: DeleteUser :one
-DELETE FROM users
-WHERE user_id = $1
+UPDATE users
+SET
+    deleted_at = now(),
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = $1
+WHERE user_id = $2
+  AND deleted_at IS NULL
+  AND ($3::int IS NULL OR version = $3)
     RETURNING user_id
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, userID pgtype.UUID) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, deleteUser, userID)
+type DeleteUserParams struct {
+	UpdatedBy       pgtype.Text
+	UserID          pgtype.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, deleteUser, arg.UpdatedBy, arg.UserID, arg.ExpectedVersion)
 	var user_id pgtype.UUID
 	err := row.Scan(&user_id)
 	return user_id, err
 }
 
-const getAllUsers = `-- name: GetAllUsers :many
-SELECT user_id, first_name, last_name, email, phone, age, status FROM users ORDER BY first_name
+const getUser = `-- name: GetUser :one
+SELECT user_id, first_name, last_name, email, phone, status, version, deleted_at, created_at, updated_at, created_by, updated_by, date_of_birth FROM users WHERE user_id = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetUser(ctx context.Context, userID pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Phone,
+		&i.Status,
+		&i.Version,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.DateOfBirth,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT user_id, first_name, last_name, email, phone, status, version, deleted_at, created_at, updated_at, created_by, updated_by, date_of_birth FROM users
+WHERE user_id = $1
+    FOR UPDATE
 `
 
-func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, getAllUsers)
+func (q *Queries) GetUserForUpdate(ctx context.Context, userID pgtype.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.Phone,
+		&i.Status,
+		&i.Version,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+		&i.DateOfBirth,
+	)
+	return i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT user_id, first_name, last_name, email, phone, status, version, deleted_at, created_at, updated_at, created_by, updated_by, date_of_birth FROM users
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR status = $1)
+  AND ($2::text IS NULL OR lower(email) = lower($2))
+  AND ($3::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => $3::int))::date)
+  AND ($4::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => $4::int + 1))::date)
+  AND ($5::text IS NULL
+    OR lower(first_name) LIKE $5
+    OR lower(last_name) LIKE $5)
+  AND ($6::timestamptz IS NULL OR created_at > $6)
+  AND ($7::timestamptz IS NULL OR created_at < $7)
+  AND ($8::timestamptz IS NULL OR updated_at > $8)
+  AND ($9::timestamptz IS NULL OR updated_at < $9)
+ORDER BY
+    CASE WHEN $10::text = 'first_name' AND NOT $11::bool THEN first_name END ASC,
+    CASE WHEN $10::text = 'first_name' AND $11::bool THEN first_name END DESC,
+    CASE WHEN $10::text = 'last_name' AND NOT $11::bool THEN last_name END ASC,
+    CASE WHEN $10::text = 'last_name' AND $11::bool THEN last_name END DESC,
+    CASE WHEN $10::text = 'email' AND NOT $11::bool THEN email END ASC,
+    CASE WHEN $10::text = 'email' AND $11::bool THEN email END DESC,
+    CASE WHEN $10::text = 'date_of_birth' AND NOT $11::bool THEN date_of_birth END ASC,
+    CASE WHEN $10::text = 'date_of_birth' AND $11::bool THEN date_of_birth END DESC,
+    CASE WHEN $10::text = 'created_at' AND NOT $11::bool THEN created_at END ASC,
+    CASE WHEN $10::text = 'created_at' AND $11::bool THEN created_at END DESC,
+    CASE WHEN $10::text = 'updated_at' AND NOT $11::bool THEN updated_at END ASC,
+    CASE WHEN $10::text = 'updated_at' AND $11::bool THEN updated_at END DESC,
+    CASE WHEN $11::bool THEN user_id END DESC,
+    user_id
+LIMIT $13 OFFSET $12
+`
+
+type ListUsersParams struct {
+	Status        pgtype.Text
+	Email         pgtype.Text
+	MinAge        pgtype.Int4
+	MaxAge        pgtype.Int4
+	NamePrefix    pgtype.Text
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	UpdatedAfter  pgtype.Timestamptz
+	UpdatedBefore pgtype.Timestamptz
+	SortColumn    string
+	SortDesc      bool
+	PageOffset    int32
+	PageLimit     int32
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsers,
+		arg.Status,
+		arg.Email,
+		arg.MinAge,
+		arg.MaxAge,
+		arg.NamePrefix,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.SortColumn,
+		arg.SortDesc,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -88,8 +309,14 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 			&i.LastName,
 			&i.Email,
 			&i.Phone,
-			&i.Age,
 			&i.Status,
+			&i.Version,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.DateOfBirth,
 		); err != nil {
 			return nil, err
 		}
@@ -101,65 +328,399 @@ func (q *Queries) GetAllUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const getUser = `-- name: GetUser :one
-SELECT user_id, first_name, last_name, email, phone, age, status FROM users WHERE user_id = $1 LIMIT 1
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT user_id, first_name, last_name, email, phone, status, version, deleted_at, created_at, updated_at, created_by, updated_by, date_of_birth FROM users
+WHERE deleted_at IS NULL
+  AND ($1::text IS NULL OR status = $1)
+  AND ($2::text IS NULL OR lower(email) = lower($2))
+  AND ($3::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => $3::int))::date)
+  AND ($4::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => $4::int + 1))::date)
+  AND ($5::text IS NULL
+    OR lower(first_name) LIKE $5
+    OR lower(last_name) LIKE $5)
+  AND ($6::timestamptz IS NULL OR created_at > $6)
+  AND ($7::timestamptz IS NULL OR created_at < $7)
+  AND ($8::timestamptz IS NULL OR updated_at > $8)
+  AND ($9::timestamptz IS NULL OR updated_at < $9)
+  AND (
+    ($10::text = 'first_name' AND NOT $11::bool AND (first_name, user_id) > ($12::text, $13::uuid)) OR
+    ($10::text = 'first_name' AND $11::bool AND (first_name, user_id) < ($12::text, $13::uuid)) OR
+    ($10::text = 'last_name' AND NOT $11::bool AND (last_name, user_id) > ($12::text, $13::uuid)) OR
+    ($10::text = 'last_name' AND $11::bool AND (last_name, user_id) < ($12::text, $13::uuid)) OR
+    ($10::text = 'email' AND NOT $11::bool AND (email, user_id) > ($12::text, $13::uuid)) OR
+    ($10::text = 'email' AND $11::bool AND (email, user_id) < ($12::text, $13::uuid)) OR
+    ($10::text = 'date_of_birth' AND NOT $11::bool AND (date_of_birth, user_id) > ($14::date, $13::uuid)) OR
+    ($10::text = 'date_of_birth' AND $11::bool AND (date_of_birth, user_id) < ($14::date, $13::uuid)) OR
+    ($10::text = 'created_at' AND NOT $11::bool AND (created_at, user_id) > ($15::timestamptz, $13::uuid)) OR
+    ($10::text = 'created_at' AND $11::bool AND (created_at, user_id) < ($15::timestamptz, $13::uuid)) OR
+    ($10::text = 'updated_at' AND NOT $11::bool AND (updated_at, user_id) > ($15::timestamptz, $13::uuid)) OR
+    ($10::text = 'updated_at' AND $11::bool AND (updated_at, user_id) < ($15::timestamptz, $13::uuid))
+  )
+ORDER BY
+    CASE WHEN $10::text = 'first_name' AND NOT $11::bool THEN first_name END ASC,
+    CASE WHEN $10::text = 'first_name' AND $11::bool THEN first_name END DESC,
+    CASE WHEN $10::text = 'last_name' AND NOT $11::bool THEN last_name END ASC,
+    CASE WHEN $10::text = 'last_name' AND $11::bool THEN last_name END DESC,
+    CASE WHEN $10::text = 'email' AND NOT $11::bool THEN email END ASC,
+    CASE WHEN $10::text = 'email' AND $11::bool THEN email END DESC,
+    CASE WHEN $10::text = 'date_of_birth' AND NOT $11::bool THEN date_of_birth END ASC,
+    CASE WHEN $10::text = 'date_of_birth' AND $11::bool THEN date_of_birth END DESC,
+    CASE WHEN $10::text = 'created_at' AND NOT $11::bool THEN created_at END ASC,
+    CASE WHEN $10::text = 'created_at' AND $11::bool THEN created_at END DESC,
+    CASE WHEN $10::text = 'updated_at' AND NOT $11::bool THEN updated_at END ASC,
+    CASE WHEN $10::text = 'updated_at' AND $11::bool THEN updated_at END DESC,
+    CASE WHEN $11::bool THEN user_id END DESC,
+    user_id
+LIMIT $16
 `
 
-func (q *Queries) GetUser(ctx context.Context, userID pgtype.UUID) (User, error) {
-	row := q.db.QueryRow(ctx, getUser, userID)
-	var i User
+type ListUsersAfterParams struct {
+	Status        pgtype.Text
+	Email         pgtype.Text
+	MinAge        pgtype.Int4
+	MaxAge        pgtype.Int4
+	NamePrefix    pgtype.Text
+	CreatedAfter  pgtype.Timestamptz
+	CreatedBefore pgtype.Timestamptz
+	UpdatedAfter  pgtype.Timestamptz
+	UpdatedBefore pgtype.Timestamptz
+	SortColumn    string
+	SortDesc      bool
+	AfterText     string
+	AfterID       pgtype.UUID
+	AfterDate     pgtype.Date
+	AfterTime     pgtype.Timestamptz
+	PageLimit     int32
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersAfter,
+		arg.Status,
+		arg.Email,
+		arg.MinAge,
+		arg.MaxAge,
+		arg.NamePrefix,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.SortColumn,
+		arg.SortDesc,
+		arg.AfterText,
+		arg.AfterID,
+		arg.AfterDate,
+		arg.AfterTime,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.Phone,
+			&i.Status,
+			&i.Version,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.UpdatedBy,
+			&i.DateOfBirth,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedUsers = `-- name: PurgeDeletedUsers :execrows
+DELETE FROM users
+WHERE deleted_at < $1
+`
+
+func (q *Queries) PurgeDeletedUsers(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedUsers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const purgeUser = `-- name: PurgeUser :one
+DELETE FROM users
+WHERE user_id = $1
+    RETURNING user_id
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, userID pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, purgeUser, userID)
+	var user_id pgtype.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const replaceUser = `-- name: ReplaceUser :one
+UPDATE users
+SET
+    first_name    = $1,
+    last_name     = $2,
+    email         = $3,
+    phone         = $4,
+    date_of_birth = $5,
+    status        = $6,
+    version       = version + 1,
+    updated_at    = now(),
+    updated_by    = $7
+WHERE user_id = $8
+  AND deleted_at IS NULL
+  AND version = $9
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by
+`
+
+type ReplaceUserParams struct {
+	FirstName       string
+	LastName        string
+	Email           string
+	Phone           string
+	DateOfBirth     pgtype.Date
+	Status          string
+	UpdatedBy       pgtype.Text
+	UserID          pgtype.UUID
+	ExpectedVersion int32
+}
+
+type ReplaceUserRow struct {
+	UserID    pgtype.UUID
+	Email     string
+	Status    string
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	CreatedBy pgtype.Text
+	UpdatedBy pgtype.Text
+}
+
+func (q *Queries) ReplaceUser(ctx context.Context, arg ReplaceUserParams) (ReplaceUserRow, error) {
+	row := q.db.QueryRow(ctx, replaceUser,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.Phone,
+		arg.DateOfBirth,
+		arg.Status,
+		arg.UpdatedBy,
+		arg.UserID,
+		arg.ExpectedVersion,
+	)
+	var i ReplaceUserRow
 	err := row.Scan(
 		&i.UserID,
-		&i.FirstName,
-		&i.LastName,
 		&i.Email,
-		&i.Phone,
-		&i.Age,
 		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
 	)
 	return i, err
 }
 
-const updateUser = `-- name: UpdateUser :one
+const restoreUser = `-- name: RestoreUser :one
 UPDATE users
 SET
-    first_name = COALESCE($2, first_name),
-    last_name  = COALESCE($3, last_name),
-    email      = COALESCE($4, email),
-    phone      = COALESCE($5, phone),
-    age        = COALESCE($6, age),
-    status     = COALESCE($7, status)
-WHERE user_id = $1
-    RETURNING user_id, email, status
+    deleted_at = NULL,
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = $1
+WHERE user_id = $2
+  AND deleted_at IS NOT NULL
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by
 `
 
-type UpdateUserParams struct {
+type RestoreUserParams struct {
+	UpdatedBy pgtype.Text
+	UserID    pgtype.UUID
+}
+
+type RestoreUserRow struct {
 	UserID    pgtype.UUID
-	FirstName string
-	LastName  string
 	Email     string
-	Phone     string
-	Age       int32
-	Status    int32
+	Status    string
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	CreatedBy pgtype.Text
+	UpdatedBy pgtype.Text
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, arg RestoreUserParams) (RestoreUserRow, error) {
+	row := q.db.QueryRow(ctx, restoreUser, arg.UpdatedBy, arg.UserID)
+	var i RestoreUserRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET
+    first_name    = COALESCE($1, first_name),
+    last_name     = COALESCE($2, last_name),
+    email         = COALESCE($3, email),
+    phone         = COALESCE($4, phone),
+    date_of_birth = COALESCE($5, date_of_birth),
+    status        = COALESCE($6, status),
+    version       = version + 1,
+    updated_at    = now(),
+    updated_by    = $7
+WHERE user_id = $8
+  AND deleted_at IS NULL
+  AND ($9::int IS NULL OR version = $9)
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by
+`
+
+type UpdateUserParams struct {
+	FirstName       pgtype.Text
+	LastName        pgtype.Text
+	Email           pgtype.Text
+	Phone           pgtype.Text
+	DateOfBirth     pgtype.Date
+	Status          pgtype.Text
+	UpdatedBy       pgtype.Text
+	UserID          pgtype.UUID
+	ExpectedVersion pgtype.Int4
 }
 
 type UpdateUserRow struct {
-	UserID pgtype.UUID
-	Email  string
-	Status int32
+	UserID    pgtype.UUID
+	Email     string
+	Status    string
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	CreatedBy pgtype.Text
+	UpdatedBy pgtype.Text
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
 	row := q.db.QueryRow(ctx, updateUser,
-		arg.UserID,
 		arg.FirstName,
 		arg.LastName,
 		arg.Email,
 		arg.Phone,
-		arg.Age,
+		arg.DateOfBirth,
 		arg.Status,
+		arg.UpdatedBy,
+		arg.UserID,
+		arg.ExpectedVersion,
 	)
 	var i UpdateUserRow
-	err := row.Scan(&i.UserID, &i.Email, &i.Status)
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
+	return i, err
+}
+
+const updateUserStatus = `-- name: UpdateUserStatus :one
+UPDATE users
+SET
+    status     = $1,
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = $2
+WHERE user_id = $3
+  AND deleted_at IS NULL
+  AND ($4::int IS NULL OR version = $4)
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by
+`
+
+type UpdateUserStatusParams struct {
+	Status          string
+	UpdatedBy       pgtype.Text
+	UserID          pgtype.UUID
+	ExpectedVersion pgtype.Int4
+}
+
+type UpdateUserStatusRow struct {
+	UserID    pgtype.UUID
+	Email     string
+	Status    string
+	Version   int32
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	CreatedBy pgtype.Text
+	UpdatedBy pgtype.Text
+}
+
+func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (UpdateUserStatusRow, error) {
+	row := q.db.QueryRow(ctx, updateUserStatus,
+		arg.Status,
+		arg.UpdatedBy,
+		arg.UserID,
+		arg.ExpectedVersion,
+	)
+	var i UpdateUserStatusRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.UpdatedBy,
+	)
 	return i, err
 }
+
+const useCustomPlans = `-- name: UseCustomPlans :exec
+SET LOCAL plan_cache_mode = force_custom_plan
+`
+
+// Lets Postgres prune the inactive sort branches of the listing queries for
+// each execution, so they can use the listing indexes. Only lasts until the
+// end of the transaction.
+func (q *Queries) UseCustomPlans(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, useCustomPlans)
+	return err
+}
+
+const userExists = `-- name: UserExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND deleted_at IS NULL)
+`
+
+func (q *Queries) UserExists(ctx context.Context, userID pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, userExists, userID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"user-management/internal/migration"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Database checks that the pool can reach Postgres. Like the other checks it
+// logs the underlying error and reports only a generic one, since health
+// reports are served without authentication.
+func Database(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) Result {
+		if err := pool.Ping(ctx); err != nil {
+			slog.Error("Health check failed", "check", "database", "error", err)
+			return Result{Status: StatusDown, Error: "database unreachable"}
+		}
+		return Result{Status: StatusUp}
+	}
+}
+
+// Schema checks the schema against the binary's migrations. A dirty schema,
+// or one behind the binary, is down. One ahead of it is only degraded: it is
+// what instances still running the previous release see during a rolling
+// deploy once the new release has migrated.
+func Schema(pool *pgxpool.Pool) Check {
+	return func(ctx context.Context) Result {
+		available, err := migration.Available()
+		if err != nil {
+			slog.Error("Health check failed", "check", "schema", "error", err)
+			return Result{Status: StatusDown, Error: "migrations unreadable"}
+		}
+
+		status := migration.Status{Latest: available[len(available)-1].Version}
+
+		// golang-migrate keeps a single row in schema_migrations.
+		err = pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&status.Version, &status.Dirty)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Health check failed", "check", "schema", "error", err)
+			return Result{Status: StatusDown, Error: "schema version unreadable"}
+		}
+
+		result := Result{
+			Status: StatusUp,
+			Details: map[string]any{
+				"version": status.Version,
+				"latest":  status.Latest,
+				"dirty":   status.Dirty,
+			},
+		}
+
+		switch {
+		case status.Dirty:
+			result.Status, result.Error = StatusDown, status.Check().Error()
+		case status.Version < status.Latest:
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("schema version %d is behind this binary (%d)", status.Version, status.Latest)
+		case status.Version > status.Latest:
+			result.Status, result.Error = StatusDegraded, status.Check().Error()
+		}
+
+		return result
+	}
+}
+
+// Pool reports how many of the pool's connections are in use. It is
+// degraded once the share in use reaches threshold, since requests then
+// start queueing for connections.
+func Pool(pool *pgxpool.Pool, threshold float64) Check {
+	return func(ctx context.Context) Result {
+		stat := pool.Stat()
+		saturation := float64(stat.AcquiredConns()) / float64(stat.MaxConns())
+
+		result := Result{
+			Status: StatusUp,
+			Details: map[string]any{
+				"acquired":   stat.AcquiredConns(),
+				"idle":       stat.IdleConns(),
+				"total":      stat.TotalConns(),
+				"max":        stat.MaxConns(),
+				"saturation": saturation,
+			},
+		}
+
+		if saturation >= threshold {
+			result.Status = StatusDegraded
+			result.Error = fmt.Sprintf("%d of %d connections in use", stat.AcquiredConns(), stat.MaxConns())
+		}
+
+		return result
+	}
+}
@@ -0,0 +1,129 @@
+// Package health runs the checks behind the liveness, readiness and health
+// endpoints.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp Status = "up"
+	// StatusDegraded means the dependency works but needs attention; it does
+	// not make the instance unready.
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Result is the outcome of one check. Details are shown to operators.
+type Result struct {
+	Status   Status
+	Error    string
+	Details  map[string]any
+	Duration time.Duration
+}
+
+// Check reports on one dependency.
+type Check func(ctx context.Context) Result
+
+// Report is the outcome of every check. Status is the worst of them, or down
+// while the instance is draining.
+type Report struct {
+	Status   Status
+	Draining bool
+	Checks   map[string]Result
+}
+
+// Ready reports whether the instance should receive traffic.
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Checker runs named checks. The zero value has no checks and is ready.
+type Checker struct {
+	// Timeout bounds each check; a check still running is reported down.
+	Timeout time.Duration
+
+	names    []string
+	checks   []Check
+	draining atomic.Bool
+}
+
+// Add registers check under name.
+func (c *Checker) Add(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+}
+
+// Drain marks the instance as shutting down so that it reports not ready
+// while in-flight requests finish.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Run runs every check concurrently.
+func (c *Checker) Run(ctx context.Context) Report {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:   StatusUp,
+		Draining: c.draining.Load(),
+		Checks:   make(map[string]Result, len(results)),
+	}
+
+	for i, result := range results {
+		report.Checks[c.names[i]] = result
+
+		switch {
+		case result.Status == StatusDown:
+			report.Status = StatusDown
+		case result.Status == StatusDegraded && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+
+	if report.Draining {
+		report.Status = StatusDown
+	}
+
+	return report
+}
+
+// run times check and reports it down if it outlives ctx.
+func run(ctx context.Context, check Check) Result {
+	started := time.Now()
+	done := make(chan Result, 1)
+
+	go func() { done <- check(ctx) }()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = Result{Status: StatusDown, Error: ctx.Err().Error()}
+	}
+
+	result.Duration = time.Since(started)
+
+	return result
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTP counts handled requests and their latency.
+type HTTP struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewHTTP(registerer prometheus.Registerer) *HTTP {
+	labels := []string{"method", "route", "status"}
+
+	h := &HTTP{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Requests handled, by method, route pattern and status code.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to handle requests, by method, route pattern and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	registerer.MustRegister(h.requests, h.duration)
+
+	return h
+}
+
+// Observe records a handled request. route must be the matched route
+// pattern, such as /users/{id}, never the raw path.
+func (h *HTTP) Observe(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	h.requests.WithLabelValues(method, route, code).Inc()
+	h.duration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
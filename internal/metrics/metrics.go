@@ -0,0 +1,38 @@
+// Package metrics defines the Prometheus metrics served on /metrics. Labels
+// only ever hold values from small fixed sets, such as route patterns and
+// error kinds, so the number of series stays bounded.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "user_management"
+
+// Metrics holds the registry that is served and the metrics the API records
+// into. Collectors reading state on demand, such as the pool's, are
+// registered on Registry by whoever owns that state.
+type Metrics struct {
+	Registry   *prometheus.Registry
+	HTTP       *HTTP
+	Repository *Repository
+	Outbox     *Outbox
+}
+
+// New returns metrics registered on a fresh registry, along with the Go
+// runtime and process metrics.
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return &Metrics{
+		Registry:   registry,
+		HTTP:       NewHTTP(registry),
+		Repository: NewRepository(registry),
+		Outbox:     NewOutbox(registry),
+	}
+}
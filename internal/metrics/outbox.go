@@ -0,0 +1,44 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Outbox counts the outcomes of publishing outbox messages.
+type Outbox struct {
+	published *prometheus.CounterVec
+	parked    prometheus.Counter
+}
+
+func NewOutbox(registerer prometheus.Registerer) *Outbox {
+	o := &Outbox{
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "publish_attempts_total",
+			Help:      "Attempts to publish outbox messages, by result.",
+		}, []string{"result"}),
+		parked: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "outbox",
+			Name:      "parked_messages_total",
+			Help:      "Outbox messages parked after running out of attempts.",
+		}),
+	}
+
+	registerer.MustRegister(o.published, o.parked)
+
+	return o
+}
+
+// Observe records a publish that returned err and whether the message was
+// parked because of it.
+func (o *Outbox) Observe(err error, parked bool) {
+	if err == nil {
+		o.published.WithLabelValues("success").Inc()
+		return
+	}
+
+	o.published.WithLabelValues("failure").Inc()
+	if parked {
+		o.parked.Inc()
+	}
+}
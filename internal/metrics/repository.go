@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"time"
+	"user-management/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Repository times user repository calls and counts their errors.
+type Repository struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func NewRepository(registerer prometheus.Registerer) *Repository {
+	r := &Repository{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Time taken by user repository calls, by operation.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "operation_errors_total",
+			Help:      "User repository calls that returned an error, by operation and error kind.",
+		}, []string{"operation", "kind"}),
+	}
+
+	registerer.MustRegister(r.duration, r.errors)
+
+	return r
+}
+
+// Observe records a call to operation, such as GetById, that returned err.
+func (r *Repository) Observe(operation string, elapsed time.Duration, err error) {
+	r.duration.WithLabelValues(operation).Observe(elapsed.Seconds())
+	if err != nil {
+		r.errors.WithLabelValues(operation, domain.ErrorKindOf(err).String()).Inc()
+	}
+}
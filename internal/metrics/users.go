@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"user-management/domain"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UserCounter counts users by status; domain.UserRepository satisfies it.
+type UserCounter interface {
+	CountByStatus(c context.Context) (map[domain.UserStatus]int64, error)
+}
+
+// UserCollector reports how many users are in each status at scrape time.
+// Every status is reported, with zero when it has no users, so series do not
+// disappear.
+type UserCollector struct {
+	users   UserCounter
+	timeout time.Duration
+	desc    *prometheus.Desc
+}
+
+// NewUserCollector returns a collector that gives up on counting after
+// timeout, leaving the gauge out of that scrape.
+func NewUserCollector(users UserCounter, timeout time.Duration) *UserCollector {
+	return &UserCollector{
+		users:   users,
+		timeout: timeout,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "users"),
+			"Users that are not deleted, by status.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+func (uc *UserCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- uc.desc
+}
+
+func (uc *UserCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), uc.timeout)
+	defer cancel()
+
+	counts, err := uc.users.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(uc.desc, err)
+		return
+	}
+
+	for _, status := range domain.UserStatuses() {
+		ch <- prometheus.MustNewConstMetric(uc.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
@@ -0,0 +1,157 @@
+// Package migration applies the database migrations embedded in the binary
+// and reports where the schema stands relative to them.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"slices"
+	"strings"
+	"user-management/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migration is one embedded migration.
+type Migration struct {
+	Version uint
+	Name    string
+}
+
+// Available lists the embedded migrations in version order.
+func Available() ([]Migration, error) {
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]Migration, 0, len(files))
+	for _, file := range files {
+		parsed, err := source.Parse(file)
+		if err != nil {
+			return nil, fmt.Errorf("parsing migration file name %q: %w", file, err)
+		}
+		available = append(available, Migration{Version: parsed.Version, Name: parsed.Identifier})
+	}
+
+	slices.SortFunc(available, func(a, b Migration) int { return int(a.Version) - int(b.Version) })
+
+	return available, nil
+}
+
+// Status is where the schema stands. Version is 0 when no migration has been
+// applied. Dirty means the migration to Version failed part way and the
+// schema must be repaired by hand before it is forced to a version.
+type Status struct {
+	Version uint
+	Dirty   bool
+	// Latest is the newest migration embedded in the binary.
+	Latest uint
+}
+
+// Check returns an error unless the binary can work with the schema: it must
+// not be dirty or newer than the binary's latest migration.
+func (s Status) Check() error {
+	switch {
+	case s.Dirty:
+		return fmt.Errorf("schema is dirty at version %d; repair it and run migrate force", s.Version)
+	case s.Version > s.Latest:
+		return fmt.Errorf("schema version %d is newer than this binary understands (%d)", s.Version, s.Latest)
+	}
+	return nil
+}
+
+// Migrator runs the embedded migrations against one database. Concurrent
+// migrators on the same database wait for each other.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+// New returns a migrator for the Postgres database at databaseURL, a
+// postgres:// connection string.
+func New(databaseURL string) (*Migrator, error) {
+	files, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", files, strings.Replace(databaseURL, "postgres://", "pgx5://", 1))
+	if err != nil {
+		return nil, fmt.Errorf("opening database for migrations: %w", err)
+	}
+	m.Log = logger{}
+
+	return &Migrator{migrate: m}, nil
+}
+
+func (mg *Migrator) Close() error {
+	sourceErr, databaseErr := mg.migrate.Close()
+	return errors.Join(sourceErr, databaseErr)
+}
+
+// Up applies every pending migration.
+func (mg *Migrator) Up() error {
+	return ignoreNoChange(mg.migrate.Up())
+}
+
+// Down reverts the last steps migrations.
+func (mg *Migrator) Down(steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps to revert must be at least 1, got %d", steps)
+	}
+	return ignoreNoChange(mg.migrate.Steps(-steps))
+}
+
+// Goto migrates up or down to version.
+func (mg *Migrator) Goto(version uint) error {
+	return ignoreNoChange(mg.migrate.Migrate(version))
+}
+
+// Force records version as applied and clean without running anything; -1
+// records that no migration is applied. It is how a dirty schema is marked
+// repaired.
+func (mg *Migrator) Force(version int) error {
+	return mg.migrate.Force(version)
+}
+
+func (mg *Migrator) Status() (Status, error) {
+	available, err := Available()
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	if len(available) > 0 {
+		status.Latest = available[len(available)-1].Version
+	}
+
+	status.Version, status.Dirty, err = mg.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		err = nil
+	}
+
+	return status, err
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// logger reports each migration as it is applied.
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) {
+	log.Printf("migrate: "+strings.TrimSuffix(format, "\n"), v...)
+}
+
+func (logger) Verbose() bool {
+	return false
+}
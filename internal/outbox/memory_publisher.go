@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps published messages in memory, for tests. Setting Err
+// makes every publish fail with it.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+	Err      error
+}
+
+func (m *MemoryPublisher) Publish(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.messages = append(m.messages, message)
+
+	return nil
+}
+
+// Messages returns what has been published so far, oldest first.
+func (m *MemoryPublisher) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
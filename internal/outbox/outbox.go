@@ -0,0 +1,160 @@
+// Package outbox delivers events that were written to the outbox table in the
+// same transaction as the change they describe. Delivery is at least once:
+// a message whose publish succeeded may be sent again if marking it delivered
+// fails or its claim runs out first. A message that keeps failing is parked
+// after MaxAttempts so the events behind it can go out.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"user-management/internal/metrics"
+
+	"github.com/google/uuid"
+)
+
+// Message is an event waiting in the outbox.
+type Message struct {
+	ID      int64
+	EventID uuid.UUID
+	Type    string
+	// Key identifies what the event is about, such as the user ID, for
+	// publishers that partition by it.
+	Key      uuid.UUID
+	Payload  []byte
+	Attempts int
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+type Store interface {
+	// Claim returns up to limit pending messages, oldest first, and holds
+	// them back from other callers for lease. The claim is committed before
+	// it returns so no locks are held while the messages are published.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Message, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	// RecordFailure counts a failed attempt and makes the message pending
+	// again, or parks it so it is not claimed any more.
+	RecordFailure(ctx context.Context, id int64, lastError string, park bool) error
+	// Release gives up the claim on messages that were not attempted.
+	Release(ctx context.Context, ids []int64) error
+	// DeleteDelivered removes messages delivered more than retention ago and
+	// reports how many it removed. Pending and parked messages are kept.
+	DeleteDelivered(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// Dispatcher moves messages from a Store to a Publisher.
+type Dispatcher struct {
+	Store     Store
+	Publisher Publisher
+	// Interval is the time between polls of an empty or failing outbox;
+	// zero or less disables dispatching.
+	Interval  time.Duration
+	BatchSize int
+	// Lease is how long claimed messages are held back from other
+	// dispatchers; it must outlast publishing a whole batch.
+	Lease time.Duration
+	// MaxAttempts is how many failed publishes park a message; zero or less
+	// retries it forever.
+	MaxAttempts int
+	// Metrics is optional.
+	Metrics *metrics.Outbox
+}
+
+// Run dispatches until ctx is done. A full batch is followed straight away by
+// the next one so a backlog drains without waiting for the next poll.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := d.DispatchOnce(ctx)
+		if err != nil {
+			slog.Error("Dispatching outbox", "error", err)
+		}
+
+		if err == nil && delivered > 0 && delivered == d.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce publishes one batch of messages in order and reports how many
+// were delivered. The first message that fails without being parked ends the
+// batch so later events do not overtake it; a parked one is skipped.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	messages, err := d.Store.Claim(ctx, d.BatchSize, d.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	// Record outcomes even if ctx is cancelled mid-batch, so a delivered
+	// message is not left to be published again once its lease runs out.
+	record := context.WithoutCancel(ctx)
+	delivered := 0
+
+	for i, message := range messages {
+		publishErr := d.Publisher.Publish(ctx, message)
+		if publishErr == nil {
+			if err := d.Store.MarkDelivered(record, message.ID); err != nil {
+				return delivered, err
+			}
+			delivered++
+			d.observe(message, nil, false)
+			continue
+		}
+
+		park := d.MaxAttempts > 0 && message.Attempts+1 >= d.MaxAttempts
+		if err := d.Store.RecordFailure(record, message.ID, publishErr.Error(), park); err != nil {
+			return delivered, err
+		}
+		d.observe(message, publishErr, park)
+
+		if park {
+			continue
+		}
+
+		rest := make([]int64, 0, len(messages)-i-1)
+		for _, later := range messages[i+1:] {
+			rest = append(rest, later.ID)
+		}
+		if len(rest) > 0 {
+			if err := d.Store.Release(record, rest); err != nil {
+				return delivered, err
+			}
+		}
+		break
+	}
+
+	return delivered, nil
+}
+
+func (d *Dispatcher) observe(message Message, err error, parked bool) {
+	if parked {
+		slog.Warn("Parked outbox message",
+			"id", message.ID,
+			"type", message.Type,
+			"event_id", message.EventID,
+			"attempts", message.Attempts+1,
+			"error", err)
+	}
+	if d.Metrics != nil {
+		d.Metrics.Observe(err, parked)
+	}
+}
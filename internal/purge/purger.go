@@ -0,0 +1,76 @@
+package purge
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-management/domain"
+	"user-management/internal/outbox"
+)
+
+// Purger periodically removes data that has outlived its retention period:
+// soft-deleted users, expired idempotency keys and delivered outbox messages.
+type Purger struct {
+	Users           domain.UserRepository
+	IdempotencyKeys domain.IdempotencyRepository
+	Outbox          outbox.Store
+	// Retention is how long a soft-deleted user is kept before it is purged.
+	Retention time.Duration
+	// OutboxRetention is how long a delivered outbox message is kept; zero
+	// or less keeps them forever.
+	OutboxRetention time.Duration
+	// Interval is the time between purges; zero or less disables purging.
+	Interval time.Duration
+}
+
+// Run purges once straight away and then every Interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	if p.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeOnce(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce removes users deleted more than Retention before now, along with
+// expired idempotency keys and outbox messages delivered more than
+// OutboxRetention ago. Failures are logged and retried on the next run.
+func (p *Purger) PurgeOnce(ctx context.Context, now time.Time) {
+	users, err := p.Users.PurgeDeleted(ctx, now.Add(-p.Retention))
+	if err != nil {
+		log.Printf("purging deleted users: %v", err)
+	} else if users > 0 {
+		log.Printf("purged %d deleted users", users)
+	}
+
+	if p.IdempotencyKeys != nil {
+		keys, err := p.IdempotencyKeys.DeleteExpired(ctx)
+		if err != nil {
+			log.Printf("purging expired idempotency keys: %v", err)
+		} else if keys > 0 {
+			log.Printf("purged %d expired idempotency keys", keys)
+		}
+	}
+
+	if p.Outbox == nil || p.OutboxRetention <= 0 {
+		return
+	}
+
+	messages, err := p.Outbox.DeleteDelivered(ctx, p.OutboxRetention)
+	if err != nil {
+		log.Printf("purging delivered outbox messages: %v", err)
+	} else if messages > 0 {
+		log.Printf("purged %d delivered outbox messages", messages)
+	}
+}
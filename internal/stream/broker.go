@@ -0,0 +1,149 @@
+// Package stream fans user events out to live subscribers such as the
+// server-sent events endpoint. Every instance hears the events committed by
+// any instance through Postgres LISTEN/NOTIFY and keeps the most recent ones,
+// so a subscriber that reconnects can resume from the last event it saw.
+package stream
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind by before
+// it is dropped.
+const subscriberBuffer = 64
+
+// Event is a user event as published to subscribers. Data is the event's
+// JSON encoding.
+type Event struct {
+	ID     string
+	Type   string
+	UserID uuid.UUID
+	Data   []byte
+}
+
+// Broker hands published events to every subscriber and remembers the last
+// few for subscribers resuming after a disconnect.
+type Broker struct {
+	mu          sync.Mutex
+	capacity    int
+	recent      []Event
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBroker returns a broker that keeps the last capacity events for replay.
+func NewBroker(capacity int) *Broker {
+	return &Broker{
+		capacity:    max(capacity, 0),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives the events published after it was made. Its channel
+// is closed when the subscriber falls too far behind, the broker is reset or
+// closed, or Close is called; the subscriber should then reconnect from the
+// last event it saw.
+type Subscription struct {
+	broker *Broker
+	events chan Event
+}
+
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.drop(s)
+}
+
+// Subscribe starts a subscription along with the backlog of remembered
+// events published after lastEventID, oldest first. With no lastEventID the
+// backlog is empty. resumed is false when lastEventID has been forgotten, in
+// which case the subscriber may have missed events and the backlog holds
+// everything still remembered.
+func (b *Broker) Subscribe(lastEventID string) (subscription *Subscription, backlog []Event, resumed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription = &Subscription{broker: b, events: make(chan Event, subscriberBuffer)}
+	if b.closed {
+		close(subscription.events)
+	} else {
+		b.subscribers[subscription] = struct{}{}
+	}
+
+	if lastEventID == "" {
+		return subscription, nil, true
+	}
+
+	for i, event := range b.recent {
+		if event.ID == lastEventID {
+			return subscription, append([]Event(nil), b.recent[i+1:]...), true
+		}
+	}
+
+	return subscription, append([]Event(nil), b.recent...), false
+}
+
+// Publish remembers event and sends it to every subscriber. Subscribers that
+// cannot keep up are dropped rather than allowed to hold up the rest.
+func (b *Broker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.capacity > 0 {
+		if len(b.recent) == b.capacity {
+			copy(b.recent, b.recent[1:])
+			b.recent = b.recent[:len(b.recent)-1]
+		}
+		b.recent = append(b.recent, event)
+	}
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			b.drop(subscription)
+		}
+	}
+}
+
+// Reset forgets the remembered events and drops every subscriber. It is
+// used when events may have been missed, so that reconnecting subscribers
+// learn they cannot resume.
+func (b *Broker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.recent = nil
+	for subscription := range b.subscribers {
+		b.drop(subscription)
+	}
+}
+
+// Close drops every subscriber and ends new subscriptions straight away. It
+// lets open streams finish when the server shuts down.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.drop(subscription)
+	}
+}
+
+// drop must be called with b.mu held.
+func (b *Broker) drop(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
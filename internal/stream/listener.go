@@ -0,0 +1,90 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"user-management/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel is the notification channel user events are sent on; it must
+// match the NotifyUserEvent query.
+const Channel = "user_events"
+
+// Listener publishes the user events notified on Channel to a broker. It
+// holds one connection out of the pool for as long as it runs.
+type Listener struct {
+	Pool   *pgxpool.Pool
+	Broker *Broker
+	// RetryDelay is the wait before listening again after losing the
+	// connection.
+	RetryDelay time.Duration
+}
+
+// Run listens until ctx is done, reconnecting whenever the connection is
+// lost. Events notified while disconnected are missed, so the broker is
+// reset on reconnecting.
+func (l *Listener) Run(ctx context.Context) {
+	listening := false
+
+	for {
+		err := l.listen(ctx, func() {
+			if listening {
+				l.Broker.Reset()
+			}
+			listening = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("listening for user events: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.RetryDelay):
+		}
+	}
+}
+
+// listen calls started once it is listening and then publishes
+// notifications until the connection fails or ctx is done.
+func (l *Listener) listen(ctx context.Context, started func()) error {
+	pooled, err := l.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// Take the connection out of the pool so that a connection still
+	// listening is never handed to anyone else.
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	started()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event domain.UserEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("decoding user event notification: %v", err)
+			continue
+		}
+
+		l.Broker.Publish(Event{
+			ID:     event.ID.String(),
+			Type:   string(event.Type),
+			UserID: event.UserId,
+			Data:   []byte(notification.Payload),
+		})
+	}
+}
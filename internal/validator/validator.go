@@ -1,11 +1,125 @@
 package validator
 
 import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"user-management/domain"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
+	"golang.org/x/text/language"
 )
 
 var Validate *validator.Validate
 
+// Translators holds the locales validation messages can be rendered in.
+// English is the fallback for any language a client asks for that is not
+// supported.
+var Translators *ut.UniversalTranslator
+
 func Init() {
 	Validate = validator.New()
+
+	// Report fields by the names clients send rather than Go field names.
+	Validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	english := en.New()
+	Translators = ut.New(english, english, es.New())
+
+	enTranslator, _ := Translators.GetTranslator("en")
+	_ = enTranslations.RegisterDefaultTranslations(Validate, enTranslator)
+
+	esTranslator, _ := Translators.GetTranslator("es")
+	_ = esTranslations.RegisterDefaultTranslations(Validate, esTranslator)
+
+	_ = Validate.RegisterValidation("notfuture", notFuture)
+	_ = Validate.RegisterValidation("minage", minAge)
+
+	registerTranslation("notfuture", map[string]string{
+		"en": "{0} must not be in the future",
+		"es": "{0} no puede estar en el futuro",
+	})
+	registerTranslation("minage", map[string]string{
+		"en": "{0} must make the user at least {1} years old",
+		"es": "{0} debe indicar una edad de al menos {1} años",
+	})
+	registerTranslation("http_url", map[string]string{
+		"en": "{0} must be an http or https URL",
+		"es": "{0} debe ser una URL http o https",
+	})
+	registerTranslation("excluded_with", map[string]string{
+		"en": "{0} must not be combined with {1}",
+		"es": "{0} no puede combinarse con {1}",
+	})
+}
+
+// TranslatorFor picks the translator that best matches an Accept-Language
+// header, honouring the client's quality values.
+func TranslatorFor(acceptLanguage string) ut.Translator {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		locales = append(locales, base.String())
+	}
+
+	translator, _ := Translators.FindTranslator(locales...)
+
+	return translator
+}
+
+// registerTranslation adds messages for a tag that the bundled translations
+// do not cover in every locale. {0} is the field name and {1} the tag param.
+func registerTranslation(tag string, messages map[string]string) {
+	for locale, message := range messages {
+		translator, _ := Translators.GetTranslator(locale)
+
+		_ = Validate.RegisterTranslation(tag, translator,
+			func(ut ut.Translator) error {
+				return ut.Add(tag, message, true)
+			},
+			func(ut ut.Translator, fe validator.FieldError) string {
+				translated, _ := ut.T(tag, fe.Field(), fe.Param())
+				return translated
+			},
+		)
+	}
+}
+
+// notFuture validates a YYYY-MM-DD date that is no later than today.
+func notFuture(fl validator.FieldLevel) bool {
+	date, err := time.Parse(time.DateOnly, fl.Field().String())
+	if err != nil {
+		return false
+	}
+	return !date.After(time.Now())
+}
+
+// minAge validates a YYYY-MM-DD date of birth of someone who is at least the
+// tag's parameter in years old today.
+func minAge(fl validator.FieldLevel) bool {
+	dateOfBirth, err := time.Parse(time.DateOnly, fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	years, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+
+	return domain.AgeOn(dateOfBirth, time.Now()) >= years
 }
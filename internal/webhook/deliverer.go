@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxRetryDelay caps the exponential backoff between attempts.
+const maxRetryDelay = 6 * time.Hour
+
+// Deliverer sends due deliveries to their subscribers.
+type Deliverer struct {
+	Store Store
+	// Client sends the requests; nil means http.DefaultClient.
+	Client *http.Client
+	// Interval is the time between polls for due deliveries; zero or less
+	// disables delivery.
+	Interval  time.Duration
+	BatchSize int
+	// Timeout bounds each request to a subscriber.
+	Timeout time.Duration
+	// MaxAttempts is how many failed attempts dead-letter a delivery.
+	MaxAttempts int
+	// RetryDelay is the wait after the first failed attempt; it doubles
+	// with every further failure.
+	RetryDelay time.Duration
+}
+
+// Run delivers once straight away and then every Interval until ctx is done.
+func (d *Deliverer) Run(ctx context.Context) {
+	if d.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverOnce(ctx); err != nil {
+			log.Printf("delivering webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverOnce sends one batch of due deliveries concurrently and reports how
+// many were attempted.
+func (d *Deliverer) DeliverOnce(ctx context.Context) (int, error) {
+	// Hold the deliveries long enough for every request to time out before
+	// another instance may pick them up.
+	deliveries, err := d.Store.ClaimDue(ctx, d.BatchSize, 2*d.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Deliverer) deliver(ctx context.Context, delivery Delivery) {
+	ctx, span := otel.Tracer("user-management/internal/webhook").Start(ctx, "webhook.deliver",
+		trace.WithAttributes(
+			attribute.Int64("webhook.delivery.id", delivery.ID),
+			attribute.String("webhook.event.id", delivery.EventID.String()),
+			attribute.String("webhook.event.type", delivery.EventType),
+		),
+	)
+	defer span.End()
+
+	attempt := Attempt{DeliveryID: delivery.ID, AttemptedAt: time.Now()}
+
+	statusCode, err := d.send(ctx, delivery)
+	attempt.StatusCode = statusCode
+	attempt.Duration = time.Since(attempt.AttemptedAt)
+
+	delivery.Attempts++
+
+	switch {
+	case err == nil && attempt.StatusCode >= 200 && attempt.StatusCode < 300:
+		delivery.Status = DeliveryDelivered
+		delivery.DeliveredAt = time.Now()
+		delivery.LastError = ""
+	default:
+		if err != nil {
+			attempt.Error = err.Error()
+		} else {
+			attempt.Error = fmt.Sprintf("subscriber responded %d", attempt.StatusCode)
+		}
+		delivery.LastError = attempt.Error
+		span.SetStatus(codes.Error, attempt.Error)
+
+		if delivery.Attempts >= d.MaxAttempts {
+			delivery.Status = DeliveryDead
+		} else {
+			delivery.NextAttemptAt = time.Now().Add(RetryDelay(d.RetryDelay, delivery.Attempts))
+		}
+	}
+
+	// Record the outcome even if ctx was cancelled mid-request.
+	if err := d.Store.RecordAttempt(context.WithoutCancel(ctx), delivery, attempt); err != nil {
+		log.Printf("recording webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+func (d *Deliverer) send(ctx context.Context, delivery Delivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(IDHeader, delivery.EventID.String())
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return response.StatusCode, nil
+}
+
+// RetryDelay is how long to wait after the given number of failed attempts:
+// base, then doubling each time, up to six hours.
+func RetryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,116 @@
+// Package webhook POSTs user events to subscribed URLs. Events published
+// through Fanout become one delivery per matching subscription, which a
+// Deliverer sends with a signature, retrying with exponential backoff until
+// the receiver accepts it or it runs out of attempts and is dead-lettered.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+	"user-management/internal/outbox"
+
+	"github.com/google/uuid"
+)
+
+// Headers sent with every delivery. Receivers should check the signature
+// and use the ID to discard duplicates.
+const (
+	IDHeader        = "X-Webhook-Id"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+type Subscription struct {
+	ID         uuid.UUID
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// Delivery is one event on its way to one subscription.
+type Delivery struct {
+	ID             int64
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+	// DeliveredAt is zero until the receiver accepts the delivery.
+	DeliveredAt time.Time
+	// URL and Secret are filled in for claimed deliveries.
+	URL    string
+	Secret string
+}
+
+// Attempt records one try at sending a delivery. StatusCode is zero when no
+// response was received.
+type Attempt struct {
+	ID          int64
+	DeliveryID  int64
+	AttemptedAt time.Time
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+}
+
+type Store interface {
+	CreateSubscription(ctx context.Context, subscription Subscription) (Subscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	// DeleteSubscription removes the subscription and its deliveries.
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	// Enqueue creates a pending delivery of the event for every subscription
+	// to its type. Enqueuing the same event again adds nothing.
+	Enqueue(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte) error
+	// ClaimDue returns up to limit pending deliveries whose next attempt is
+	// due and holds them back from other callers for lease.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	// RecordAttempt logs attempt and saves the delivery's new state.
+	RecordAttempt(ctx context.Context, delivery Delivery, attempt Attempt) error
+	// ListDeliveries returns the subscription's deliveries after afterID,
+	// oldest first, optionally only those with status.
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, status DeliveryStatus, afterID int64, limit int) ([]Delivery, error)
+	ListAttempts(ctx context.Context, subscriptionID uuid.UUID, deliveryID int64) ([]Attempt, error)
+	// Replay makes a delivery pending again with a fresh set of attempts,
+	// whatever state it was in.
+	Replay(ctx context.Context, subscriptionID uuid.UUID, deliveryID int64) (Delivery, error)
+}
+
+// Sign returns the signature header value for body sent at timestamp (Unix
+// seconds): the hex HMAC-SHA256 of "timestamp.body" keyed with the
+// subscription's secret.
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Fanout is an outbox.Publisher that queues each event for every webhook
+// subscribed to it.
+type Fanout struct {
+	Store Store
+}
+
+func (f Fanout) Publish(ctx context.Context, message outbox.Message) error {
+	return f.Store.Enqueue(ctx, message.EventID, message.Type, message.Payload)
+}
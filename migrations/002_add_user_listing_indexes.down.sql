@@ -0,0 +1,7 @@
+DROP INDEX users_lower_last_name_pattern_idx;
+DROP INDEX users_lower_first_name_pattern_idx;
+DROP INDEX users_lower_email_idx;
+DROP INDEX users_age_user_id_idx;
+DROP INDEX users_email_user_id_idx;
+DROP INDEX users_last_name_user_id_idx;
+DROP INDEX users_first_name_user_id_idx;
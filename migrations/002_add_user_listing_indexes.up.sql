@@ -0,0 +1,7 @@
+CREATE INDEX users_first_name_user_id_idx ON users (first_name, user_id);
+CREATE INDEX users_last_name_user_id_idx ON users (last_name, user_id);
+CREATE INDEX users_email_user_id_idx ON users (email, user_id);
+CREATE INDEX users_age_user_id_idx ON users (age, user_id);
+CREATE INDEX users_lower_email_idx ON users (lower(email));
+CREATE INDEX users_lower_first_name_pattern_idx ON users (lower(first_name) text_pattern_ops);
+CREATE INDEX users_lower_last_name_pattern_idx ON users (lower(last_name) text_pattern_ops);
@@ -0,0 +1,11 @@
+CREATE TABLE idempotency_keys (
+idempotency_key   TEXT PRIMARY KEY,
+request_hash      TEXT NOT NULL,
+status_code       INT,
+response_headers  JSONB,
+response_body     BYTEA,
+created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+expires_at        TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
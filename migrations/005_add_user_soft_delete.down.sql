@@ -0,0 +1,7 @@
+-- Soft-deleted rows may share an email with a live user, so they have to go
+-- before the table-wide unique constraint can come back.
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DROP INDEX users_deleted_at_idx;
+DROP INDEX users_email_live_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users DROP COLUMN deleted_at;
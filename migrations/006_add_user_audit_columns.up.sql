@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN created_by TEXT,
+    ADD COLUMN updated_by TEXT;
+CREATE INDEX users_created_at_user_id_idx ON users (created_at, user_id);
+CREATE INDEX users_updated_at_user_id_idx ON users (updated_at, user_id);
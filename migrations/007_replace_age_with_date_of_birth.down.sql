@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN age INT;
+UPDATE users SET age = date_part('year', age(date_of_birth))::int;
+ALTER TABLE users ALTER COLUMN age SET NOT NULL;
+ALTER TABLE users ADD CHECK (age >= 0);
+DROP INDEX users_date_of_birth_user_id_idx;
+ALTER TABLE users DROP COLUMN date_of_birth;
+CREATE INDEX users_age_user_id_idx ON users (age, user_id);
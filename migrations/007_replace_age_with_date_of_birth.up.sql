@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN date_of_birth DATE;
+-- Only the age is known, so place each birthday half-way through the year
+-- of birth it implies.
+UPDATE users SET date_of_birth = (current_date - make_interval(years => age, months => 6))::date;
+ALTER TABLE users ALTER COLUMN date_of_birth SET NOT NULL;
+DROP INDEX users_age_user_id_idx;
+ALTER TABLE users DROP COLUMN age;
+CREATE INDEX users_date_of_birth_user_id_idx ON users (date_of_birth, user_id);
@@ -0,0 +1,7 @@
+DROP TABLE user_status_changes;
+ALTER TABLE users DROP CONSTRAINT users_status_check;
+ALTER TABLE users ALTER COLUMN status TYPE INT USING CASE status
+    WHEN 'active' THEN 1
+    WHEN 'pending' THEN 0
+    ELSE 2
+END;
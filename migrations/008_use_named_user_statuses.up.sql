@@ -0,0 +1,20 @@
+-- 0 was never stored on purpose (creation defaults it to active), so it
+-- becomes pending; inactive users become deactivated.
+ALTER TABLE users ALTER COLUMN status TYPE TEXT USING CASE status
+    WHEN 1 THEN 'active'
+    WHEN 2 THEN 'deactivated'
+    ELSE 'pending'
+END;
+ALTER TABLE users ADD CONSTRAINT users_status_check
+    CHECK (status IN ('pending', 'active', 'suspended', 'locked', 'deactivated'));
+
+CREATE TABLE user_status_changes (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     UUID NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status   TEXT NOT NULL,
+    reason      TEXT,
+    changed_by  TEXT,
+    changed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX user_status_changes_user_id_idx ON user_status_changes (user_id, changed_at);
@@ -0,0 +1,30 @@
+CREATE TABLE user_history (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users (user_id) ON DELETE CASCADE,
+    revision   INT NOT NULL,
+    operation  TEXT NOT NULL CHECK (operation IN ('created', 'updated', 'deleted', 'restored')),
+    changes    JSONB NOT NULL,
+    changed_by TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, revision)
+);
+CREATE INDEX user_history_user_id_changed_at_idx ON user_history (user_id, changed_at);
+
+-- Earlier revisions were never recorded, so every existing user starts its
+-- history with one revision holding its current state.
+INSERT INTO user_history (user_id, revision, operation, changes, changed_by, changed_at)
+SELECT
+    user_id,
+    version,
+    CASE WHEN deleted_at IS NULL THEN 'updated' ELSE 'deleted' END,
+    jsonb_build_object(
+        'firstName', jsonb_build_object('from', '', 'to', first_name),
+        'lastName', jsonb_build_object('from', '', 'to', last_name),
+        'email', jsonb_build_object('from', '', 'to', email),
+        'phone', jsonb_build_object('from', '', 'to', phone),
+        'dateOfBirth', jsonb_build_object('from', '', 'to', to_char(date_of_birth, 'YYYY-MM-DD')),
+        'status', jsonb_build_object('from', '', 'to', status)
+    ),
+    updated_by,
+    updated_at
+FROM users;
@@ -0,0 +1,32 @@
+-- target_user_id is deliberately not a foreign key: entries must outlive the
+-- users they mention.
+CREATE TABLE audit_log (
+    id             BIGSERIAL PRIMARY KEY,
+    occurred_at    TIMESTAMPTZ NOT NULL,
+    actor          TEXT NOT NULL,
+    source_ip      TEXT NOT NULL,
+    request_id     TEXT NOT NULL,
+    method         TEXT NOT NULL,
+    route          TEXT NOT NULL,
+    target_user_id UUID,
+    status         INT NOT NULL,
+    outcome        TEXT NOT NULL CHECK (outcome IN ('success', 'failure')),
+    prev_hash      TEXT NOT NULL,
+    hash           TEXT NOT NULL UNIQUE
+);
+CREATE INDEX audit_log_actor_idx ON audit_log (actor, id);
+CREATE INDEX audit_log_target_user_id_idx ON audit_log (target_user_id, id);
+CREATE INDEX audit_log_occurred_at_idx ON audit_log (occurred_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_or_delete
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
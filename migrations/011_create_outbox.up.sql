@@ -0,0 +1,12 @@
+CREATE TABLE outbox (
+    id           BIGSERIAL PRIMARY KEY,
+    event_id     UUID NOT NULL UNIQUE,
+    event_type   TEXT NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload      JSONB NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ,
+    attempts     INT NOT NULL DEFAULT 0,
+    last_error   TEXT
+);
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL;
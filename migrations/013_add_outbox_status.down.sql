@@ -0,0 +1,6 @@
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL;
+
+ALTER TABLE outbox
+    DROP COLUMN locked_until,
+    DROP COLUMN status;
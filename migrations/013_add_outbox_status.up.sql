@@ -0,0 +1,11 @@
+-- Messages are claimed for a short lease and published after the claim has
+-- committed. A message that keeps failing is parked after a number of
+-- attempts so it no longer holds back the events behind it.
+ALTER TABLE outbox
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'parked')),
+    ADD COLUMN locked_until TIMESTAMPTZ;
+
+UPDATE outbox SET status = 'delivered' WHERE delivered_at IS NOT NULL;
+
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE status = 'pending';
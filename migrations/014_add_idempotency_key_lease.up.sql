@@ -0,0 +1,4 @@
+-- A key is held by the request processing it only until locked_until, so a
+-- claim left behind by a crashed process can be taken over long before the
+-- key expires. Claims already in progress can be taken over straight away.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMPTZ NOT NULL DEFAULT now();
@@ -0,0 +1,3 @@
+-- Identifies the claim currently holding a key, so that a request whose lease
+-- ran out cannot complete or release a key another request has taken over.
+ALTER TABLE idempotency_keys ADD COLUMN claim_token UUID;
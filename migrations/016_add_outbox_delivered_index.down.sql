@@ -0,0 +1 @@
+DROP INDEX outbox_delivered_idx;
@@ -0,0 +1,2 @@
+-- Delivered events are deleted once they are older than the retention period.
+CREATE INDEX outbox_delivered_idx ON outbox (delivered_at) WHERE status = 'delivered';
@@ -0,0 +1,35 @@
+-- name: LockAuditLog :exec
+LOCK TABLE audit_log IN SHARE ROW EXCLUSIVE MODE;
+
+-- name: GetLastAuditHash :one
+SELECT hash FROM audit_log
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log (
+    occurred_at,
+    actor,
+    source_ip,
+    request_id,
+    method,
+    route,
+    target_user_id,
+    status,
+    outcome,
+    prev_hash,
+    hash
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    RETURNING id;
+
+-- name: ListAuditEntries :many
+SELECT * FROM audit_log
+WHERE id > @after_id
+  AND (sqlc.narg('actor')::text IS NULL OR actor = sqlc.narg('actor'))
+  AND (sqlc.narg('target_user_id')::uuid IS NULL OR target_user_id = sqlc.narg('target_user_id'))
+  AND (sqlc.narg('outcome')::text IS NULL OR outcome = sqlc.narg('outcome'))
+  AND (sqlc.narg('occurred_after')::timestamptz IS NULL OR occurred_at > sqlc.narg('occurred_after'))
+  AND (sqlc.narg('occurred_before')::timestamptz IS NULL OR occurred_at < sqlc.narg('occurred_before'))
+ORDER BY id
+LIMIT @page_limit;
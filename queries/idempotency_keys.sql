@@ -0,0 +1,52 @@
+-- name: ClaimIdempotencyKey :one
+-- Takes over keys that have expired, and unfinished claims whose lease has
+-- run out.
+INSERT INTO idempotency_keys (
+    idempotency_key,
+    request_hash,
+    expires_at,
+    locked_until,
+    claim_token
+)
+VALUES (
+    @idempotency_key,
+    @request_hash,
+    now() + make_interval(secs => @ttl_seconds::int),
+    now() + make_interval(secs => @lease_seconds::int),
+    @claim_token
+)
+ON CONFLICT (idempotency_key) DO UPDATE
+SET
+    request_hash     = EXCLUDED.request_hash,
+    status_code      = NULL,
+    response_headers = NULL,
+    response_body    = NULL,
+    created_at       = now(),
+    expires_at       = EXCLUDED.expires_at,
+    locked_until     = EXCLUDED.locked_until,
+    claim_token      = EXCLUDED.claim_token
+WHERE idempotency_keys.expires_at <= now()
+   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until <= now())
+    RETURNING idempotency_key;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE idempotency_key = $1 LIMIT 1;
+
+-- name: CompleteIdempotencyKey :execrows
+UPDATE idempotency_keys
+SET
+    status_code      = @status_code,
+    response_headers = @response_headers,
+    response_body    = @response_body
+WHERE idempotency_key = @idempotency_key
+  AND claim_token = @claim_token
+  AND status_code IS NULL;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE idempotency_key = @idempotency_key
+  AND claim_token = @claim_token
+  AND status_code IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at <= now();
@@ -0,0 +1,48 @@
+-- name: CreateOutboxEvent :exec
+INSERT INTO outbox (
+    event_id,
+    event_type,
+    aggregate_id,
+    payload
+)
+VALUES ($1, $2, $3, $4);
+
+-- name: ClaimOutboxEvents :many
+UPDATE outbox
+SET locked_until = now() + make_interval(secs => @lease_seconds::float8)
+WHERE id IN (
+    SELECT id FROM outbox
+    WHERE status = 'pending' AND (locked_until IS NULL OR locked_until <= now())
+    ORDER BY id
+    LIMIT @batch_size
+        FOR UPDATE SKIP LOCKED
+)
+    RETURNING *;
+
+-- name: MarkOutboxEventDelivered :exec
+UPDATE outbox
+SET
+    status       = 'delivered',
+    delivered_at = now(),
+    attempts     = attempts + 1,
+    locked_until = NULL
+WHERE id = $1;
+
+-- name: RecordOutboxEventFailure :exec
+UPDATE outbox
+SET
+    status       = @status,
+    attempts     = attempts + 1,
+    last_error   = @last_error,
+    locked_until = NULL
+WHERE id = @id;
+
+-- name: ReleaseOutboxEvents :exec
+UPDATE outbox
+SET locked_until = NULL
+WHERE id = ANY (@ids::bigint[]);
+
+-- name: DeleteDeliveredOutboxEvents :execrows
+DELETE FROM outbox
+WHERE status = 'delivered'
+  AND delivered_at < now() - make_interval(secs => @retention_seconds::float8);
@@ -0,0 +1,4 @@
+-- name: NotifyUserEvent :exec
+-- Listeners on the user_events channel receive the payload when the
+-- transaction commits, and never if it rolls back.
+SELECT pg_notify('user_events', @payload::text);
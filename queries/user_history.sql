@@ -0,0 +1,21 @@
+-- name: CreateUserRevision :exec
+INSERT INTO user_history (
+    user_id,
+    revision,
+    operation,
+    changes,
+    changed_by
+)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListUserRevisions :many
+SELECT * FROM user_history
+WHERE user_id = $1
+ORDER BY revision;
+
+-- name: ListUserRevisionsUpTo :many
+SELECT * FROM user_history
+WHERE user_id = @user_id
+  AND (sqlc.narg('revision')::int IS NULL OR revision <= sqlc.narg('revision'))
+  AND (sqlc.narg('changed_at')::timestamptz IS NULL OR changed_at <= sqlc.narg('changed_at'))
+ORDER BY revision;
@@ -0,0 +1,9 @@
+-- name: CreateUserStatusChange :exec
+INSERT INTO user_status_changes (
+    user_id,
+    from_status,
+    to_status,
+    reason,
+    changed_by
+)
+VALUES ($1, $2, $3, $4, $5);
@@ -5,31 +5,207 @@ INSERT INTO users (
     last_name,
     email,
     phone,
-    age,
-    status
+    date_of_birth,
+    status,
+    created_by,
+    updated_by
 )
-VALUES ( $1, $2, $3, $4, $5, $6, $7)
-    RETURNING user_id, email, status;
+VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $8)
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by;
 
 -- name: GetUser :one
-SELECT * FROM users WHERE user_id = $1 LIMIT 1;
+SELECT * FROM users WHERE user_id = $1 AND deleted_at IS NULL LIMIT 1;
 
--- name: GetAllUsers :many
-SELECT * FROM users ORDER BY first_name;
+-- name: UseCustomPlans :exec
+-- Lets Postgres prune the inactive sort branches of the listing queries for
+-- each execution, so they can use the listing indexes. Only lasts until the
+-- end of the transaction.
+SET LOCAL plan_cache_mode = force_custom_plan;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('email')::text IS NULL OR lower(email) = lower(sqlc.narg('email')))
+  AND (sqlc.narg('min_age')::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => sqlc.narg('min_age')::int))::date)
+  AND (sqlc.narg('max_age')::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => sqlc.narg('max_age')::int + 1))::date)
+  AND (sqlc.narg('name_prefix')::text IS NULL
+    OR lower(first_name) LIKE sqlc.narg('name_prefix')
+    OR lower(last_name) LIKE sqlc.narg('name_prefix'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at > sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('updated_after')::timestamptz IS NULL OR updated_at > sqlc.narg('updated_after'))
+  AND (sqlc.narg('updated_before')::timestamptz IS NULL OR updated_at < sqlc.narg('updated_before'))
+ORDER BY
+    CASE WHEN @sort_column::text = 'first_name' AND NOT @sort_desc::bool THEN first_name END ASC,
+    CASE WHEN @sort_column::text = 'first_name' AND @sort_desc::bool THEN first_name END DESC,
+    CASE WHEN @sort_column::text = 'last_name' AND NOT @sort_desc::bool THEN last_name END ASC,
+    CASE WHEN @sort_column::text = 'last_name' AND @sort_desc::bool THEN last_name END DESC,
+    CASE WHEN @sort_column::text = 'email' AND NOT @sort_desc::bool THEN email END ASC,
+    CASE WHEN @sort_column::text = 'email' AND @sort_desc::bool THEN email END DESC,
+    CASE WHEN @sort_column::text = 'date_of_birth' AND NOT @sort_desc::bool THEN date_of_birth END ASC,
+    CASE WHEN @sort_column::text = 'date_of_birth' AND @sort_desc::bool THEN date_of_birth END DESC,
+    CASE WHEN @sort_column::text = 'created_at' AND NOT @sort_desc::bool THEN created_at END ASC,
+    CASE WHEN @sort_column::text = 'created_at' AND @sort_desc::bool THEN created_at END DESC,
+    CASE WHEN @sort_column::text = 'updated_at' AND NOT @sort_desc::bool THEN updated_at END ASC,
+    CASE WHEN @sort_column::text = 'updated_at' AND @sort_desc::bool THEN updated_at END DESC,
+    CASE WHEN @sort_desc::bool THEN user_id END DESC,
+    user_id
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: ListUsersAfter :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('email')::text IS NULL OR lower(email) = lower(sqlc.narg('email')))
+  AND (sqlc.narg('min_age')::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => sqlc.narg('min_age')::int))::date)
+  AND (sqlc.narg('max_age')::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => sqlc.narg('max_age')::int + 1))::date)
+  AND (sqlc.narg('name_prefix')::text IS NULL
+    OR lower(first_name) LIKE sqlc.narg('name_prefix')
+    OR lower(last_name) LIKE sqlc.narg('name_prefix'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at > sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('updated_after')::timestamptz IS NULL OR updated_at > sqlc.narg('updated_after'))
+  AND (sqlc.narg('updated_before')::timestamptz IS NULL OR updated_at < sqlc.narg('updated_before'))
+  AND (
+    (@sort_column::text = 'first_name' AND NOT @sort_desc::bool AND (first_name, user_id) > (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'first_name' AND @sort_desc::bool AND (first_name, user_id) < (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'last_name' AND NOT @sort_desc::bool AND (last_name, user_id) > (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'last_name' AND @sort_desc::bool AND (last_name, user_id) < (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'email' AND NOT @sort_desc::bool AND (email, user_id) > (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'email' AND @sort_desc::bool AND (email, user_id) < (@after_text::text, @after_id::uuid)) OR
+    (@sort_column::text = 'date_of_birth' AND NOT @sort_desc::bool AND (date_of_birth, user_id) > (@after_date::date, @after_id::uuid)) OR
+    (@sort_column::text = 'date_of_birth' AND @sort_desc::bool AND (date_of_birth, user_id) < (@after_date::date, @after_id::uuid)) OR
+    (@sort_column::text = 'created_at' AND NOT @sort_desc::bool AND (created_at, user_id) > (@after_time::timestamptz, @after_id::uuid)) OR
+    (@sort_column::text = 'created_at' AND @sort_desc::bool AND (created_at, user_id) < (@after_time::timestamptz, @after_id::uuid)) OR
+    (@sort_column::text = 'updated_at' AND NOT @sort_desc::bool AND (updated_at, user_id) > (@after_time::timestamptz, @after_id::uuid)) OR
+    (@sort_column::text = 'updated_at' AND @sort_desc::bool AND (updated_at, user_id) < (@after_time::timestamptz, @after_id::uuid))
+  )
+ORDER BY
+    CASE WHEN @sort_column::text = 'first_name' AND NOT @sort_desc::bool THEN first_name END ASC,
+    CASE WHEN @sort_column::text = 'first_name' AND @sort_desc::bool THEN first_name END DESC,
+    CASE WHEN @sort_column::text = 'last_name' AND NOT @sort_desc::bool THEN last_name END ASC,
+    CASE WHEN @sort_column::text = 'last_name' AND @sort_desc::bool THEN last_name END DESC,
+    CASE WHEN @sort_column::text = 'email' AND NOT @sort_desc::bool THEN email END ASC,
+    CASE WHEN @sort_column::text = 'email' AND @sort_desc::bool THEN email END DESC,
+    CASE WHEN @sort_column::text = 'date_of_birth' AND NOT @sort_desc::bool THEN date_of_birth END ASC,
+    CASE WHEN @sort_column::text = 'date_of_birth' AND @sort_desc::bool THEN date_of_birth END DESC,
+    CASE WHEN @sort_column::text = 'created_at' AND NOT @sort_desc::bool THEN created_at END ASC,
+    CASE WHEN @sort_column::text = 'created_at' AND @sort_desc::bool THEN created_at END DESC,
+    CASE WHEN @sort_column::text = 'updated_at' AND NOT @sort_desc::bool THEN updated_at END ASC,
+    CASE WHEN @sort_column::text = 'updated_at' AND @sort_desc::bool THEN updated_at END DESC,
+    CASE WHEN @sort_desc::bool THEN user_id END DESC,
+    user_id
+LIMIT @page_limit;
+
+-- name: CountUsers :one
+SELECT count(*) FROM users
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('email')::text IS NULL OR lower(email) = lower(sqlc.narg('email')))
+  AND (sqlc.narg('min_age')::int IS NULL
+    OR date_of_birth <= (current_date - make_interval(years => sqlc.narg('min_age')::int))::date)
+  AND (sqlc.narg('max_age')::int IS NULL
+    OR date_of_birth > (current_date - make_interval(years => sqlc.narg('max_age')::int + 1))::date)
+  AND (sqlc.narg('name_prefix')::text IS NULL
+    OR lower(first_name) LIKE sqlc.narg('name_prefix')
+    OR lower(last_name) LIKE sqlc.narg('name_prefix'))
+  AND (sqlc.narg('created_after')::timestamptz IS NULL OR created_at > sqlc.narg('created_after'))
+  AND (sqlc.narg('created_before')::timestamptz IS NULL OR created_at < sqlc.narg('created_before'))
+  AND (sqlc.narg('updated_after')::timestamptz IS NULL OR updated_at > sqlc.narg('updated_after'))
+  AND (sqlc.narg('updated_before')::timestamptz IS NULL OR updated_at < sqlc.narg('updated_before'));
+
+-- name: CountUsersByStatus :many
+SELECT status, count(*) AS users FROM users
+WHERE deleted_at IS NULL
+GROUP BY status;
 
 -- name: UpdateUser :one
 UPDATE users
 SET
-    first_name = COALESCE($2, first_name),
-    last_name  = COALESCE($3, last_name),
-    email      = COALESCE($4, email),
-    phone      = COALESCE($5, phone),
-    age        = COALESCE($6, age),
-    status     = COALESCE($7, status)
-WHERE user_id = $1
-    RETURNING user_id, email, status;
+    first_name    = COALESCE(sqlc.narg('first_name'), first_name),
+    last_name     = COALESCE(sqlc.narg('last_name'), last_name),
+    email         = COALESCE(sqlc.narg('email'), email),
+    phone         = COALESCE(sqlc.narg('phone'), phone),
+    date_of_birth = COALESCE(sqlc.narg('date_of_birth'), date_of_birth),
+    status        = COALESCE(sqlc.narg('status'), status),
+    version       = version + 1,
+    updated_at    = now(),
+    updated_by    = sqlc.narg('updated_by')
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'))
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by;
+
+-- name: ReplaceUser :one
+UPDATE users
+SET
+    first_name    = @first_name,
+    last_name     = @last_name,
+    email         = @email,
+    phone         = @phone,
+    date_of_birth = @date_of_birth,
+    status        = @status,
+    version       = version + 1,
+    updated_at    = now(),
+    updated_by    = sqlc.narg('updated_by')
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND version = @expected_version
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by;
 
 -- name: DeleteUser :one
+UPDATE users
+SET
+    deleted_at = now(),
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = sqlc.narg('updated_by')
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'))
+    RETURNING user_id;
+
+-- name: RestoreUser :one
+UPDATE users
+SET
+    deleted_at = NULL,
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = sqlc.narg('updated_by')
+WHERE user_id = @user_id
+  AND deleted_at IS NOT NULL
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by;
+
+-- name: PurgeUser :one
+DELETE FROM users
+WHERE user_id = @user_id
+    RETURNING user_id;
+
+-- name: PurgeDeletedUsers :execrows
 DELETE FROM users
+WHERE deleted_at < @deleted_before;
+
+-- name: UserExists :one
+SELECT EXISTS (SELECT 1 FROM users WHERE user_id = $1 AND deleted_at IS NULL);
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
 WHERE user_id = $1
-    RETURNING user_id;
\ No newline at end of file
+    FOR UPDATE;
+
+-- name: UpdateUserStatus :one
+UPDATE users
+SET
+    status     = @status,
+    version    = version + 1,
+    updated_at = now(),
+    updated_by = sqlc.narg('updated_by')
+WHERE user_id = @user_id
+  AND deleted_at IS NULL
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'))
+    RETURNING user_id, email, status, version, created_at, updated_at, created_by, updated_by;
@@ -0,0 +1,96 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    id,
+    url,
+    event_types,
+    secret
+)
+VALUES ($1, $2, $3, $4)
+    RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT * FROM webhook_subscriptions WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT * FROM webhook_subscriptions ORDER BY created_at, id;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions WHERE id = $1;
+
+-- name: EnqueueWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    event_type,
+    payload
+)
+SELECT id, @event_id, @event_type, @payload
+FROM webhook_subscriptions
+WHERE @event_type::text = ANY (event_types)
+ON CONFLICT (subscription_id, event_id) DO NOTHING;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries d
+SET next_attempt_at = now() + make_interval(secs => @lease_seconds::float8)
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id
+  AND d.id IN (
+    SELECT id FROM webhook_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at, id
+    LIMIT @batch_size
+        FOR UPDATE SKIP LOCKED
+  )
+    RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+        d.next_attempt_at, d.last_error, d.created_at, d.delivered_at, s.url, s.secret;
+
+-- name: UpdateWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET
+    status          = @status,
+    attempts        = @attempts,
+    next_attempt_at = @next_attempt_at,
+    last_error      = sqlc.narg('last_error'),
+    delivered_at    = sqlc.narg('delivered_at')
+WHERE id = @id;
+
+-- name: CreateWebhookDeliveryAttempt :exec
+INSERT INTO webhook_delivery_attempts (
+    delivery_id,
+    attempted_at,
+    status_code,
+    error,
+    duration_ms
+)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE subscription_id = @subscription_id
+  AND id > @after_id
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+ORDER BY id
+LIMIT @page_limit;
+
+-- name: ListWebhookDeliveryAttempts :many
+SELECT a.* FROM webhook_delivery_attempts a
+JOIN webhook_deliveries d ON d.id = a.delivery_id
+WHERE d.subscription_id = @subscription_id
+  AND a.delivery_id = @delivery_id
+ORDER BY a.id;
+
+-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET
+    status          = 'pending',
+    attempts        = 0,
+    next_attempt_at = now(),
+    delivered_at    = NULL
+WHERE id = @id
+  AND subscription_id = @subscription_id
+    RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT * FROM webhook_deliveries
+WHERE id = @id
+  AND subscription_id = @subscription_id;
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-management/internal/audit"
+	"user-management/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	connectionPool *pgxpool.Pool
+	queries        *db.Queries
+	hashKey        []byte
+}
+
+// NewAuditRepository returns a store that chains entries with hashes keyed
+// with hashKey; see audit.Entry.ComputeHash.
+func NewAuditRepository(pool *pgxpool.Pool, hashKey []byte) audit.Store {
+	return &AuditRepository{
+		connectionPool: pool,
+		queries:        db.New(pool),
+		hashKey:        hashKey,
+	}
+}
+
+func (ar *AuditRepository) Append(c context.Context, entry audit.Entry) (audit.Entry, error) {
+	tx, err := ar.connectionPool.Begin(c)
+	if err != nil {
+		return audit.Entry{}, translateError(err)
+	}
+	// Rolling back after a commit is a no-op.
+	defer func() { _ = tx.Rollback(c) }()
+
+	q := ar.queries.WithTx(tx)
+
+	// The lock lets readers through but holds other appends until this one
+	// commits, so each entry sees the previous one's hash.
+	if err := q.LockAuditLog(c); err != nil {
+		return audit.Entry{}, translateError(err)
+	}
+
+	entry.PrevHash, err = q.GetLastAuditHash(c)
+	if errors.Is(err, pgx.ErrNoRows) {
+		entry.PrevHash, err = audit.GenesisHash, nil
+	}
+	if err != nil {
+		return audit.Entry{}, translateError(err)
+	}
+
+	entry.Hash = entry.ComputeHash(ar.hashKey)
+
+	entry.ID, err = q.CreateAuditEntry(c, db.CreateAuditEntryParams{
+		OccurredAt:   pgtype.Timestamptz{Time: entry.OccurredAt, Valid: true},
+		Actor:        entry.Actor,
+		SourceIp:     entry.SourceIP,
+		RequestID:    entry.RequestID,
+		Method:       entry.Method,
+		Route:        entry.Route,
+		TargetUserID: toPgNullUUID(entry.TargetUser),
+		Status:       int32(entry.Status),
+		Outcome:      string(entry.Outcome),
+		PrevHash:     entry.PrevHash,
+		Hash:         entry.Hash,
+	})
+	if err != nil {
+		return audit.Entry{}, translateError(err)
+	}
+
+	return entry, translateError(tx.Commit(c))
+}
+
+func (ar *AuditRepository) List(c context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	rows, err := ar.queries.ListAuditEntries(c, db.ListAuditEntriesParams{
+		AfterID:        filter.AfterID,
+		Actor:          toPgText(filter.Actor),
+		TargetUserID:   toPgNullUUID(filter.TargetUser),
+		Outcome:        toPgText(string(filter.Outcome)),
+		OccurredAfter:  toPgTimestamptz(filter.OccurredAfter),
+		OccurredBefore: toPgTimestamptz(filter.OccurredBefore),
+		PageLimit:      int32(filter.Limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	entries := make([]audit.Entry, 0, len(rows))
+
+	for _, row := range rows {
+		entries = append(entries, audit.Entry{
+			ID:         row.ID,
+			OccurredAt: row.OccurredAt.Time,
+			Actor:      row.Actor,
+			SourceIP:   row.SourceIp,
+			RequestID:  row.RequestID,
+			Method:     row.Method,
+			Route:      row.Route,
+			TargetUser: ToUUIDFromPgUUID(row.TargetUserID),
+			Status:     int(row.Status),
+			Outcome:    audit.Outcome(row.Outcome),
+			PrevHash:   row.PrevHash,
+			Hash:       row.Hash,
+		})
+	}
+
+	return entries, nil
+}
+
+// toPgNullUUID maps uuid.Nil to NULL.
+func toPgNullUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
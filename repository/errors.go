@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"user-management/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes the repository translates into domain errors.
+const (
+	pgUniqueViolation      = "23505"
+	pgCheckViolation       = "23514"
+	pgNotNullViolation     = "23502"
+	pgStringTooLong        = "22001"
+	pgInvalidTextValue     = "22P02"
+	pgTooManyConnections   = "53300"
+	pgQueryCanceled        = "57014"
+	pgAdminShutdown        = "57P01"
+	pgCannotConnectNow     = "57P03"
+	pgConnectionExceptions = "08"
+)
+
+// translateError maps pgx and Postgres errors onto the domain error taxonomy
+// so callers never have to inspect driver errors themselves.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("user not found", err)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return domain.NewCanceledError("request was canceled", err)
+	}
+
+	// Checked before network errors, as which pgx also reports deadlines.
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return domain.NewTimeoutError("database did not respond in time", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			return domain.NewConflictError(conflictMessage(pgErr), err)
+		case pgErr.Code == pgCheckViolation,
+			pgErr.Code == pgNotNullViolation,
+			pgErr.Code == pgStringTooLong,
+			pgErr.Code == pgInvalidTextValue:
+			return domain.NewValidationError("user violates a data constraint", err)
+		// Postgres cancels statements that outlive statement_timeout, and
+		// those pgx asks it to once the request's context is done. Callers
+		// that know their context was canceled report that instead.
+		case pgErr.Code == pgQueryCanceled:
+			return domain.NewTimeoutError("database did not respond in time", err)
+		case pgErr.Code == pgTooManyConnections,
+			pgErr.Code == pgAdminShutdown,
+			pgErr.Code == pgCannotConnectNow,
+			strings.HasPrefix(pgErr.Code, pgConnectionExceptions):
+			return domain.NewUnavailableError("database is unavailable", err)
+		}
+		return err
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) {
+		return domain.NewUnavailableError("database is unavailable", err)
+	}
+
+	return err
+}
+
+func conflictMessage(pgErr *pgconn.PgError) string {
+	if strings.Contains(pgErr.ConstraintName, "email") {
+		return "a user with this email already exists"
+	}
+	return "user conflicts with an existing user"
+}
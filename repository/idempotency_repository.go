@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-management/domain"
+	"user-management/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	queries *db.Queries
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) domain.IdempotencyRepository {
+	return &IdempotencyRepository{
+		queries: db.New(pool),
+	}
+}
+
+// claimAttempts bounds how often Claim retries when the row holding a key
+// disappears between the conflicting claim and reading it.
+const claimAttempts = 3
+
+func (ir *IdempotencyRepository) Claim(c context.Context, key string, requestHash string, ttl time.Duration, lease time.Duration) (uuid.UUID, domain.IdempotentResponse, error) {
+	for range claimAttempts {
+		claim := uuid.New()
+
+		_, err := ir.queries.ClaimIdempotencyKey(c, db.ClaimIdempotencyKeyParams{
+			IdempotencyKey: key,
+			RequestHash:    requestHash,
+			TtlSeconds:     int32(ttl.Seconds()),
+			LeaseSeconds:   int32(lease.Seconds()),
+			ClaimToken:     ToPgUUID(claim),
+		})
+
+		if err == nil {
+			return claim, domain.IdempotentResponse{}, nil
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, domain.IdempotentResponse{}, translateError(err)
+		}
+
+		stored, err := ir.queries.GetIdempotencyKey(c, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released or expired since the claim conflicted; try again.
+			continue
+		}
+		if err != nil {
+			return uuid.Nil, domain.IdempotentResponse{}, translateError(err)
+		}
+
+		response := domain.IdempotentResponse{
+			RequestHash: stored.RequestHash,
+			StatusCode:  int(stored.StatusCode.Int32),
+			Body:        stored.ResponseBody,
+		}
+
+		if stored.ResponseHeaders != nil {
+			if err := json.Unmarshal(stored.ResponseHeaders, &response.Header); err != nil {
+				return uuid.Nil, domain.IdempotentResponse{}, err
+			}
+		}
+
+		return uuid.Nil, response, nil
+	}
+
+	return uuid.Nil, domain.IdempotentResponse{}, domain.NewConflictError("a request with this Idempotency-Key is still being processed", nil)
+}
+
+func (ir *IdempotencyRepository) Complete(c context.Context, key string, claim uuid.UUID, response domain.IdempotentResponse) error {
+	header, err := json.Marshal(response.Header)
+	if err != nil {
+		return err
+	}
+
+	completed, err := ir.queries.CompleteIdempotencyKey(c, db.CompleteIdempotencyKeyParams{
+		IdempotencyKey:  key,
+		ClaimToken:      ToPgUUID(claim),
+		StatusCode:      pgtype.Int4{Int32: int32(response.StatusCode), Valid: true},
+		ResponseHeaders: header,
+		ResponseBody:    response.Body,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	if completed == 0 {
+		return domain.NewConflictError("idempotency key was taken over by another request", nil)
+	}
+
+	return nil
+}
+
+func (ir *IdempotencyRepository) Release(c context.Context, key string, claim uuid.UUID) error {
+	return translateError(ir.queries.ReleaseIdempotencyKey(c, db.ReleaseIdempotencyKeyParams{
+		IdempotencyKey: key,
+		ClaimToken:     ToPgUUID(claim),
+	}))
+}
+
+func (ir *IdempotencyRepository) DeleteExpired(c context.Context) (int64, error) {
+	deleted, err := ir.queries.DeleteExpiredIdempotencyKeys(c)
+	return deleted, translateError(err)
+}
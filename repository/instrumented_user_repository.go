@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-management/domain"
+	"user-management/internal/db"
+	"user-management/internal/metrics"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentedUserRepository records a span and metrics for every call to
+// the repository it wraps. The SQL statements run by a call are grouped
+// under its span.
+type InstrumentedUserRepository struct {
+	next    domain.UserRepository
+	tracer  trace.Tracer
+	metrics *metrics.Repository
+}
+
+// NewInstrumentedUserRepository wraps next. With nil repositoryMetrics only
+// spans are recorded.
+func NewInstrumentedUserRepository(next domain.UserRepository, repositoryMetrics *metrics.Repository) domain.UserRepository {
+	return &InstrumentedUserRepository{
+		next:    next,
+		tracer:  otel.Tracer("user-management/repository"),
+		metrics: repositoryMetrics,
+	}
+}
+
+// repositoryCall is one observed call.
+type repositoryCall struct {
+	ctx       context.Context
+	operation string
+	started   time.Time
+	span      trace.Span
+	metrics   *metrics.Repository
+}
+
+func (ir *InstrumentedUserRepository) start(c context.Context, operation string, id uuid.UUID) (context.Context, *repositoryCall) {
+	c, span := ir.tracer.Start(c, "UserRepository."+operation)
+	if id != uuid.Nil {
+		span.SetAttributes(attribute.String("user.id", id.String()))
+	}
+	return c, &repositoryCall{ctx: c, operation: operation, started: time.Now(), span: span, metrics: ir.metrics}
+}
+
+// end records err and returns it. Only internal, unavailable and timeout
+// errors mark the span failed; the other kinds are expected answers, such as
+// a missing user.
+func (cl *repositoryCall) end(err error) error {
+	defer cl.span.End()
+
+	// A statement Postgres cancelled because the client went away reads as
+	// a timeout; report it as the cancellation it is.
+	if err != nil && errors.Is(cl.ctx.Err(), context.Canceled) {
+		err = domain.NewCanceledError("request was canceled", err)
+	}
+
+	if cl.metrics != nil {
+		cl.metrics.Observe(cl.operation, time.Since(cl.started), err)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	kind := domain.ErrorKindOf(err)
+	cl.span.SetAttributes(semconv.ErrorTypeKey.String(kind.String()))
+
+	switch kind {
+	case domain.ErrorKindInternal, domain.ErrorKindUnavailable, domain.ErrorKindTimeout:
+		cl.span.RecordError(err)
+		cl.span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (ir *InstrumentedUserRepository) Create(c context.Context, user *domain.User) (created db.CreateUserRow, err error) {
+	c, call := ir.start(c, "Create", user.UserId)
+	defer func() { err = call.end(err) }()
+	return ir.next.Create(c, user)
+}
+
+func (ir *InstrumentedUserRepository) GetAll(c context.Context, query domain.UserQuery) (users []domain.User, total int64, err error) {
+	c, call := ir.start(c, "GetAll", uuid.Nil)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetAll(c, query)
+}
+
+func (ir *InstrumentedUserRepository) GetAllAfter(c context.Context, query domain.UserQuery, after domain.UserCursor) (users []domain.User, err error) {
+	c, call := ir.start(c, "GetAllAfter", uuid.Nil)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetAllAfter(c, query, after)
+}
+
+func (ir *InstrumentedUserRepository) GetById(c context.Context, id uuid.UUID) (user domain.User, err error) {
+	c, call := ir.start(c, "GetById", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetById(c, id)
+}
+
+func (ir *InstrumentedUserRepository) Update(c context.Context, id uuid.UUID, user *domain.User) (updated db.UpdateUserRow, err error) {
+	c, call := ir.start(c, "Update", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.Update(c, id, user)
+}
+
+func (ir *InstrumentedUserRepository) Replace(c context.Context, user *domain.User) (updated db.UpdateUserRow, err error) {
+	c, call := ir.start(c, "Replace", user.UserId)
+	defer func() { err = call.end(err) }()
+	return ir.next.Replace(c, user)
+}
+
+func (ir *InstrumentedUserRepository) ChangeStatus(c context.Context, id uuid.UUID, status domain.UserStatus, reason string, expectedVersion int) (updated db.UpdateUserRow, err error) {
+	c, call := ir.start(c, "ChangeStatus", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.ChangeStatus(c, id, status, reason, expectedVersion)
+}
+
+func (ir *InstrumentedUserRepository) Delete(c context.Context, id uuid.UUID, expectedVersion int) (deleted uuid.UUID, err error) {
+	c, call := ir.start(c, "Delete", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.Delete(c, id, expectedVersion)
+}
+
+func (ir *InstrumentedUserRepository) Restore(c context.Context, id uuid.UUID) (restored db.UpdateUserRow, err error) {
+	c, call := ir.start(c, "Restore", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.Restore(c, id)
+}
+
+func (ir *InstrumentedUserRepository) Purge(c context.Context, id uuid.UUID) (err error) {
+	c, call := ir.start(c, "Purge", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.Purge(c, id)
+}
+
+func (ir *InstrumentedUserRepository) GetHistory(c context.Context, id uuid.UUID) (revisions []domain.UserRevision, err error) {
+	c, call := ir.start(c, "GetHistory", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetHistory(c, id)
+}
+
+func (ir *InstrumentedUserRepository) GetRevision(c context.Context, id uuid.UUID, revision int) (user domain.User, err error) {
+	c, call := ir.start(c, "GetRevision", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetRevision(c, id, revision)
+}
+
+func (ir *InstrumentedUserRepository) GetAsOf(c context.Context, id uuid.UUID, at time.Time) (user domain.User, err error) {
+	c, call := ir.start(c, "GetAsOf", id)
+	defer func() { err = call.end(err) }()
+	return ir.next.GetAsOf(c, id, at)
+}
+
+func (ir *InstrumentedUserRepository) PurgeDeleted(c context.Context, before time.Time) (purged int64, err error) {
+	c, call := ir.start(c, "PurgeDeleted", uuid.Nil)
+	defer func() { err = call.end(err) }()
+	return ir.next.PurgeDeleted(c, before)
+}
+
+func (ir *InstrumentedUserRepository) CountByStatus(c context.Context) (counts map[domain.UserStatus]int64, err error) {
+	c, call := ir.start(c, "CountByStatus", uuid.Nil)
+	defer func() { err = call.end(err) }()
+	return ir.next.CountByStatus(c)
+}
@@ -0,0 +1,479 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"user-management/domain"
+	"user-management/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// memoryUser is a stored user along with what the database keeps beside it.
+type memoryUser struct {
+	user domain.User
+	// deletedAt is zero while the user is live.
+	deletedAt time.Time
+	history   []domain.UserRevision
+}
+
+// MemoryUserRepository keeps users in memory and behaves like UserRepository
+// backed by Postgres: the same checks in the same order, the same errors and
+// the same history. Text is sorted by byte value, which matches Postgres only
+// for collations that agree with it, such as C. Events are not published.
+type MemoryUserRepository struct {
+	mu    sync.Mutex
+	users map[uuid.UUID]*memoryUser
+}
+
+func NewMemoryUserRepository() domain.UserRepository {
+	return &MemoryUserRepository{users: make(map[uuid.UUID]*memoryUser)}
+}
+
+func (mr *MemoryUserRepository) Create(c context.Context, user *domain.User) (db.CreateUserRow, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, exists := mr.users[user.UserId]; exists {
+		return db.CreateUserRow{}, domain.NewConflictError("user conflicts with an existing user", nil)
+	}
+
+	now := memoryNow()
+	actor := domain.ActorFrom(c)
+
+	created := domain.User{
+		UserId:      user.UserId,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		Email:       user.Email,
+		Phone:       user.Phone,
+		DateOfBirth: dateOnly(user.DateOfBirth),
+		Status:      user.Status,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedBy:   actor,
+	}
+
+	if err := mr.checkConstraints(created); err != nil {
+		return db.CreateUserRow{}, err
+	}
+
+	stored := &memoryUser{user: created}
+	stored.record(domain.UserCreated, domain.User{}, created)
+	mr.users[created.UserId] = stored
+
+	row := toUpdateUserRow(created)
+
+	return db.CreateUserRow(row), nil
+}
+
+func (mr *MemoryUserRepository) GetAll(c context.Context, query domain.UserQuery) ([]domain.User, int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	users := mr.list(query)
+	total := int64(len(users))
+
+	offset := min(max(query.Offset, 0), len(users))
+	users = users[offset:]
+
+	return users[:min(max(query.Limit, 0), len(users))], total, nil
+}
+
+func (mr *MemoryUserRepository) GetAllAfter(c context.Context, query domain.UserQuery, after domain.UserCursor) ([]domain.User, error) {
+	last := domain.User{UserId: after.LastUserId}
+
+	switch query.SortBy {
+	case domain.UserSortByAge, domain.UserSortByDateOfBirth:
+		dateOfBirth, err := time.Parse(time.DateOnly, after.LastValue)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("invalid date of birth cursor %q", after.LastValue), err)
+		}
+		last.DateOfBirth = dateOfBirth
+	case domain.UserSortByCreatedAt, domain.UserSortByUpdatedAt:
+		at, err := time.Parse(time.RFC3339Nano, after.LastValue)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("invalid timestamp cursor %q", after.LastValue), err)
+		}
+		last.CreatedAt, last.UpdatedAt = at, at
+	default:
+		last.FirstName, last.LastName, last.Email = after.LastValue, after.LastValue, after.LastValue
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	descending := sortDescending(query)
+
+	var users []domain.User
+	for _, user := range mr.list(query) {
+		order := compareUsers(user, last, query.SortBy)
+		if (!descending && order > 0) || (descending && order < 0) {
+			users = append(users, user)
+		}
+	}
+
+	return users[:min(max(query.Limit, 0), len(users))], nil
+}
+
+func (mr *MemoryUserRepository) GetById(c context.Context, id uuid.UUID) (domain.User, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	stored, ok := mr.users[id]
+	if !ok || !stored.deletedAt.IsZero() {
+		return domain.User{}, domain.NewNotFoundError("user not found", nil)
+	}
+
+	return withAge(stored.user), nil
+}
+
+func (mr *MemoryUserRepository) Update(c context.Context, id uuid.UUID, user *domain.User) (db.UpdateUserRow, error) {
+	updated, err := mr.write(c, id, domain.UserUpdated, user.Status, nonZero(user.Version), func(u *domain.User) {
+		u.FirstName = coalesce(user.FirstName, u.FirstName)
+		u.LastName = coalesce(user.LastName, u.LastName)
+		u.Email = coalesce(user.Email, u.Email)
+		u.Phone = coalesce(user.Phone, u.Phone)
+		u.Status = coalesce(user.Status, u.Status)
+		if !user.DateOfBirth.IsZero() {
+			u.DateOfBirth = dateOnly(user.DateOfBirth)
+		}
+	})
+
+	return toUpdateUserRow(updated), err
+}
+
+func (mr *MemoryUserRepository) Replace(c context.Context, user *domain.User) (db.UpdateUserRow, error) {
+	expectedVersion := user.Version
+
+	replaced, err := mr.write(c, user.UserId, domain.UserUpdated, user.Status, &expectedVersion, func(u *domain.User) {
+		u.FirstName = user.FirstName
+		u.LastName = user.LastName
+		u.Email = user.Email
+		u.Phone = user.Phone
+		u.DateOfBirth = dateOnly(user.DateOfBirth)
+		u.Status = user.Status
+	})
+
+	return toUpdateUserRow(replaced), err
+}
+
+func (mr *MemoryUserRepository) ChangeStatus(c context.Context, id uuid.UUID, status domain.UserStatus, reason string, expectedVersion int) (db.UpdateUserRow, error) {
+	changed, err := mr.write(c, id, domain.UserUpdated, status, nonZero(expectedVersion), func(u *domain.User) {
+		u.Status = status
+	})
+
+	return toUpdateUserRow(changed), err
+}
+
+func (mr *MemoryUserRepository) Delete(c context.Context, id uuid.UUID, expectedVersion int) (uuid.UUID, error) {
+	if _, err := mr.write(c, id, domain.UserDeleted, "", nonZero(expectedVersion), func(u *domain.User) {}); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
+}
+
+func (mr *MemoryUserRepository) Restore(c context.Context, id uuid.UUID) (db.UpdateUserRow, error) {
+	restored, err := mr.write(c, id, domain.UserRestored, "", nil, func(u *domain.User) {})
+
+	return toUpdateUserRow(restored), err
+}
+
+func (mr *MemoryUserRepository) Purge(c context.Context, id uuid.UUID) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.users[id]; !ok {
+		return domain.NewNotFoundError("user not found", nil)
+	}
+
+	delete(mr.users, id)
+
+	return nil
+}
+
+func (mr *MemoryUserRepository) PurgeDeleted(c context.Context, before time.Time) (int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	var purged int64
+	for id, stored := range mr.users {
+		if !stored.deletedAt.IsZero() && stored.deletedAt.Before(before) {
+			delete(mr.users, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func (mr *MemoryUserRepository) CountByStatus(c context.Context) (map[domain.UserStatus]int64, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	counts := make(map[domain.UserStatus]int64)
+	for _, stored := range mr.users {
+		if stored.deletedAt.IsZero() {
+			counts[stored.user.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (mr *MemoryUserRepository) GetHistory(c context.Context, id uuid.UUID) ([]domain.UserRevision, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	stored, ok := mr.users[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("user not found", nil)
+	}
+
+	return slices.Clone(stored.history), nil
+}
+
+func (mr *MemoryUserRepository) GetRevision(c context.Context, id uuid.UUID, revision int) (domain.User, error) {
+	revisions := mr.revisionsUpTo(id, func(r domain.UserRevision) bool { return r.Revision <= revision })
+
+	return revisionOf(revisions, revision)
+}
+
+func (mr *MemoryUserRepository) GetAsOf(c context.Context, id uuid.UUID, at time.Time) (domain.User, error) {
+	revisions := mr.revisionsUpTo(id, func(r domain.UserRevision) bool { return !r.ChangedAt.After(at) })
+
+	return asOf(revisions, at)
+}
+
+// revisionsUpTo returns the user's revisions, oldest first, up to the first
+// one include rejects.
+func (mr *MemoryUserRepository) revisionsUpTo(id uuid.UUID, include func(domain.UserRevision) bool) []domain.UserRevision {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	stored, ok := mr.users[id]
+	if !ok {
+		return nil
+	}
+
+	var revisions []domain.UserRevision
+	for _, revision := range stored.history {
+		if !include(revision) {
+			break
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions
+}
+
+// write applies fn to the user and records the revision it produces, making
+// the same checks as UserRepository.write and the queries it runs, in the
+// same order: the user must exist and be deleted only when restoring, the
+// lifecycle must allow any status given, the version must be expectedVersion
+// when one is given, and the result must satisfy the table's constraints.
+func (mr *MemoryUserRepository) write(c context.Context, id uuid.UUID, op domain.UserOperation, status domain.UserStatus, expectedVersion *int, fn func(u *domain.User)) (domain.User, error) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	stored, ok := mr.users[id]
+	if !ok || stored.deletedAt.IsZero() == (op == domain.UserRestored) {
+		return domain.User{}, mr.missedWriteError(op, id)
+	}
+
+	before := stored.user
+
+	if status != "" {
+		if err := before.Status.CheckTransition(status); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	if expectedVersion != nil && before.Version != *expectedVersion {
+		return domain.User{}, domain.NewPreconditionFailedError("user has been modified since it was read", nil)
+	}
+
+	now := memoryNow()
+
+	after := before
+	fn(&after)
+	after.Version++
+	after.UpdatedAt = now
+	after.UpdatedBy = domain.ActorFrom(c)
+
+	if op != domain.UserDeleted {
+		if err := mr.checkConstraints(after); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	stored.user = after
+	switch op {
+	case domain.UserDeleted:
+		stored.deletedAt = now
+	case domain.UserRestored:
+		stored.deletedAt = time.Time{}
+	}
+	stored.record(op, before, after)
+
+	return withAge(after), nil
+}
+
+// missedWriteError explains why a write found no user to apply to, as
+// UserRepository does.
+func (mr *MemoryUserRepository) missedWriteError(op domain.UserOperation, id uuid.UUID) error {
+	stored, ok := mr.users[id]
+	if ok && stored.deletedAt.IsZero() && op == domain.UserRestored {
+		return domain.NewConflictError("user is not deleted", nil)
+	}
+
+	return domain.NewNotFoundError("user not found", nil)
+}
+
+// checkConstraints enforces what the users table does for a live user: a
+// date of birth, a known status and an email no other live user has.
+func (mr *MemoryUserRepository) checkConstraints(user domain.User) error {
+	if user.DateOfBirth.IsZero() || !user.Status.IsValid() {
+		return domain.NewValidationError("user violates a data constraint", nil)
+	}
+
+	for id, other := range mr.users {
+		if id != user.UserId && other.deletedAt.IsZero() && other.user.Email == user.Email {
+			return domain.NewConflictError("a user with this email already exists", nil)
+		}
+	}
+
+	return nil
+}
+
+// list returns the live users matching the query's filter in its sort order.
+func (mr *MemoryUserRepository) list(query domain.UserQuery) []domain.User {
+	today := time.Now()
+	filter := query.Filter
+
+	var users []domain.User
+	for _, stored := range mr.users {
+		user := withAge(stored.user)
+
+		switch {
+		case !stored.deletedAt.IsZero(),
+			filter.Status != nil && user.Status != *filter.Status,
+			filter.Email != "" && !strings.EqualFold(user.Email, filter.Email),
+			filter.MinAge != nil && domain.AgeOn(user.DateOfBirth, today) < *filter.MinAge,
+			filter.MaxAge != nil && domain.AgeOn(user.DateOfBirth, today) > *filter.MaxAge,
+			filter.NamePrefix != "" && !hasFoldedPrefix(user.FirstName, filter.NamePrefix) && !hasFoldedPrefix(user.LastName, filter.NamePrefix),
+			filter.CreatedAfter != nil && !user.CreatedAt.After(*filter.CreatedAfter),
+			filter.CreatedBefore != nil && !user.CreatedAt.Before(*filter.CreatedBefore),
+			filter.UpdatedAfter != nil && !user.UpdatedAt.After(*filter.UpdatedAfter),
+			filter.UpdatedBefore != nil && !user.UpdatedAt.Before(*filter.UpdatedBefore):
+			continue
+		}
+
+		users = append(users, user)
+	}
+
+	descending := sortDescending(query)
+	slices.SortFunc(users, func(a, b domain.User) int {
+		if descending {
+			return compareUsers(b, a, query.SortBy)
+		}
+		return compareUsers(a, b, query.SortBy)
+	})
+
+	return users
+}
+
+// record appends the revision a write from before to after produced.
+func (m *memoryUser) record(op domain.UserOperation, before, after domain.User) {
+	m.history = append(m.history, domain.UserRevision{
+		UserId:    after.UserId,
+		Revision:  after.Version,
+		Operation: op,
+		Changes:   domain.DiffUsers(before, after),
+		ChangedBy: after.UpdatedBy,
+		ChangedAt: after.UpdatedAt,
+	})
+}
+
+// compareUsers orders users by the sort column, ascending, then by user ID.
+func compareUsers(a, b domain.User, sortBy domain.UserSortField) int {
+	var order int
+
+	switch sortBy {
+	case domain.UserSortByFirstName:
+		order = strings.Compare(a.FirstName, b.FirstName)
+	case domain.UserSortByLastName:
+		order = strings.Compare(a.LastName, b.LastName)
+	case domain.UserSortByEmail:
+		order = strings.Compare(a.Email, b.Email)
+	case domain.UserSortByAge, domain.UserSortByDateOfBirth:
+		order = a.DateOfBirth.Compare(b.DateOfBirth)
+	case domain.UserSortByCreatedAt:
+		order = a.CreatedAt.Compare(b.CreatedAt)
+	case domain.UserSortByUpdatedAt:
+		order = a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+
+	if order != 0 {
+		return order
+	}
+
+	return bytes.Compare(a.UserId[:], b.UserId[:])
+}
+
+func hasFoldedPrefix(value, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix))
+}
+
+func coalesce[T ~string](value, fallback T) T {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// memoryNow returns the current time at the precision Postgres stores.
+func memoryNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// dateOnly drops the time of day from value, as storing it in a DATE column
+// does.
+func dateOnly(value time.Time) time.Time {
+	if value.IsZero() {
+		return value
+	}
+	year, month, day := value.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func withAge(user domain.User) domain.User {
+	user.Age = domain.AgeOn(user.DateOfBirth, time.Now())
+	return user
+}
+
+func toUpdateUserRow(user domain.User) db.UpdateUserRow {
+	if user.UserId == uuid.Nil {
+		return db.UpdateUserRow{}
+	}
+
+	return db.UpdateUserRow{
+		UserID:    ToPgUUID(user.UserId),
+		Email:     user.Email,
+		Status:    string(user.Status),
+		Version:   int32(user.Version),
+		CreatedAt: pgtype.Timestamptz{Time: user.CreatedAt, Valid: true},
+		UpdatedAt: pgtype.Timestamptz{Time: user.UpdatedAt, Valid: true},
+		CreatedBy: toPgText(user.CreatedBy),
+		UpdatedBy: toPgText(user.UpdatedBy),
+	}
+}
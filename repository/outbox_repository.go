@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+	"user-management/internal/db"
+	"user-management/internal/outbox"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepository struct {
+	connectionPool *pgxpool.Pool
+	queries        *db.Queries
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) outbox.Store {
+	return &OutboxRepository{
+		connectionPool: pool,
+		queries:        db.New(pool),
+	}
+}
+
+func (or *OutboxRepository) Claim(c context.Context, limit int, lease time.Duration) ([]outbox.Message, error) {
+	rows, err := or.queries.ClaimOutboxEvents(c, db.ClaimOutboxEventsParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	messages := make([]outbox.Message, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, outbox.Message{
+			ID:       row.ID,
+			EventID:  ToUUIDFromPgUUID(row.EventID),
+			Type:     row.EventType,
+			Key:      ToUUIDFromPgUUID(row.AggregateID),
+			Payload:  row.Payload,
+			Attempts: int(row.Attempts),
+		})
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery's order.
+	slices.SortFunc(messages, func(a, b outbox.Message) int { return cmp.Compare(a.ID, b.ID) })
+
+	return messages, nil
+}
+
+func (or *OutboxRepository) MarkDelivered(c context.Context, id int64) error {
+	return translateError(or.queries.MarkOutboxEventDelivered(c, id))
+}
+
+func (or *OutboxRepository) RecordFailure(c context.Context, id int64, lastError string, park bool) error {
+	status := "pending"
+	if park {
+		status = "parked"
+	}
+
+	return translateError(or.queries.RecordOutboxEventFailure(c, db.RecordOutboxEventFailureParams{
+		ID:        id,
+		Status:    status,
+		LastError: toPgText(lastError),
+	}))
+}
+
+func (or *OutboxRepository) Release(c context.Context, ids []int64) error {
+	return translateError(or.queries.ReleaseOutboxEvents(c, ids))
+}
+
+func (or *OutboxRepository) DeleteDelivered(c context.Context, retention time.Duration) (int64, error) {
+	deleted, err := or.queries.DeleteDeliveredOutboxEvents(c, retention.Seconds())
+	return deleted, translateError(err)
+}
@@ -2,14 +2,32 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 	"user-management/domain"
 	"user-management/internal/db"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// sortColumns maps the sort fields exposed by the API onto the column names
+// understood by the ListUsers query.
+var sortColumns = map[domain.UserSortField]string{
+	domain.UserSortByFirstName:   "first_name",
+	domain.UserSortByLastName:    "last_name",
+	domain.UserSortByEmail:       "email",
+	domain.UserSortByAge:         "date_of_birth",
+	domain.UserSortByDateOfBirth: "date_of_birth",
+	domain.UserSortByCreatedAt:   "created_at",
+	domain.UserSortByUpdatedAt:   "updated_at",
+}
+
 type UserRepository struct {
 	connectionPool *pgxpool.Pool
 	queries        *db.Queries
@@ -23,44 +41,152 @@ func NewUserRepository(pool *pgxpool.Pool) domain.UserRepository {
 }
 
 func (ur *UserRepository) Create(c context.Context, user *domain.User) (db.CreateUserRow, error) {
-	createdd, err := ur.queries.CreateUser(c, db.CreateUserParams{
-		UserID:    ToPgUUID(user.UserId),
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
-		Phone:     user.Phone,
-		Age:       int32(user.Age),
-		Status:    int32(user.Status),
+	var createdd db.CreateUserRow
+
+	err := ur.write(c, user.UserId, domain.UserCreated, "", "", func(q *db.Queries) error {
+		var err error
+		createdd, err = q.CreateUser(c, db.CreateUserParams{
+			UserID:      ToPgUUID(user.UserId),
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Email:       user.Email,
+			Phone:       user.Phone,
+			DateOfBirth: toPgDate(user.DateOfBirth),
+			Status:      string(user.Status),
+			CreatedBy:   toPgText(domain.ActorFrom(c)),
+		})
+		return err
 	})
 
 	created := db.CreateUserRow{
-		UserID: createdd.UserID,
-		Email:  createdd.Email,
-		Status: createdd.Status,
+		UserID:    createdd.UserID,
+		Email:     createdd.Email,
+		Status:    createdd.Status,
+		Version:   createdd.Version,
+		CreatedAt: createdd.CreatedAt,
+		UpdatedAt: createdd.UpdatedAt,
+		CreatedBy: createdd.CreatedBy,
+		UpdatedBy: createdd.UpdatedBy,
 	}
 
-	return created, err
+	return created, translateError(err)
 }
 
-func (ur *UserRepository) GetAll(c context.Context) ([]domain.User, error) {
-	dbUsers, err := ur.queries.GetAllUsers(c)
+func (ur *UserRepository) GetAll(c context.Context, query domain.UserQuery) ([]domain.User, int64, error) {
+	filter := query.Filter
+
+	var status pgtype.Text
+	if filter.Status != nil {
+		status = toPgText(string(*filter.Status))
+	}
+
+	var dbUsers []db.User
+
+	err := ur.inListingTx(c, func(q *db.Queries) error {
+		var err error
+		dbUsers, err = q.ListUsers(c, db.ListUsersParams{
+			Status:        status,
+			Email:         toPgText(filter.Email),
+			MinAge:        toPgInt4(filter.MinAge),
+			MaxAge:        toPgInt4(filter.MaxAge),
+			NamePrefix:    toPgPrefixPattern(filter.NamePrefix),
+			CreatedAfter:  toPgTimestamptz(filter.CreatedAfter),
+			CreatedBefore: toPgTimestamptz(filter.CreatedBefore),
+			UpdatedAfter:  toPgTimestamptz(filter.UpdatedAfter),
+			UpdatedBefore: toPgTimestamptz(filter.UpdatedBefore),
+			SortColumn:    sortColumns[query.SortBy],
+			SortDesc:      sortDescending(query),
+			PageOffset:    int32(query.Offset),
+			PageLimit:     int32(query.Limit),
+		})
+		return err
+	})
+
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+
+	total, err := ur.queries.CountUsers(c, db.CountUsersParams{
+		Status:        status,
+		Email:         toPgText(filter.Email),
+		MinAge:        toPgInt4(filter.MinAge),
+		MaxAge:        toPgInt4(filter.MaxAge),
+		NamePrefix:    toPgPrefixPattern(filter.NamePrefix),
+		CreatedAfter:  toPgTimestamptz(filter.CreatedAfter),
+		CreatedBefore: toPgTimestamptz(filter.CreatedBefore),
+		UpdatedAfter:  toPgTimestamptz(filter.UpdatedAfter),
+		UpdatedBefore: toPgTimestamptz(filter.UpdatedBefore),
+	})
 
 	if err != nil {
-		return nil, err
+		return nil, 0, translateError(err)
 	}
 
 	users := make([]domain.User, 0, len(dbUsers))
 
 	for _, u := range dbUsers {
-		users = append(users, domain.User{
-			UserId:    ToUUIDFromPgUUID(u.UserID),
-			FirstName: u.FirstName,
-			LastName:  u.LastName,
-			Email:     u.Email,
-			Phone:     u.Phone,
-			Age:       int(u.Age),
-			Status:    domain.UserStatus(u.Status),
-		})
+		users = append(users, toDomainUser(u))
+	}
+
+	return users, total, nil
+}
+
+func (ur *UserRepository) GetAllAfter(c context.Context, query domain.UserQuery, after domain.UserCursor) ([]domain.User, error) {
+	filter := query.Filter
+
+	var status pgtype.Text
+	if filter.Status != nil {
+		status = toPgText(string(*filter.Status))
+	}
+
+	params := db.ListUsersAfterParams{
+		Status:        status,
+		Email:         toPgText(filter.Email),
+		MinAge:        toPgInt4(filter.MinAge),
+		MaxAge:        toPgInt4(filter.MaxAge),
+		NamePrefix:    toPgPrefixPattern(filter.NamePrefix),
+		CreatedAfter:  toPgTimestamptz(filter.CreatedAfter),
+		CreatedBefore: toPgTimestamptz(filter.CreatedBefore),
+		UpdatedAfter:  toPgTimestamptz(filter.UpdatedAfter),
+		UpdatedBefore: toPgTimestamptz(filter.UpdatedBefore),
+		SortColumn:    sortColumns[query.SortBy],
+		SortDesc:      sortDescending(query),
+		AfterText:     after.LastValue,
+		AfterID:       ToPgUUID(after.LastUserId),
+		PageLimit:     int32(query.Limit),
+	}
+
+	switch query.SortBy {
+	case domain.UserSortByAge, domain.UserSortByDateOfBirth:
+		dateOfBirth, err := time.Parse(time.DateOnly, after.LastValue)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("invalid date of birth cursor %q", after.LastValue), err)
+		}
+		params.AfterDate = toPgDate(dateOfBirth)
+	case domain.UserSortByCreatedAt, domain.UserSortByUpdatedAt:
+		at, err := time.Parse(time.RFC3339Nano, after.LastValue)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("invalid timestamp cursor %q", after.LastValue), err)
+		}
+		params.AfterTime = pgtype.Timestamptz{Time: at, Valid: true}
+	}
+
+	var dbUsers []db.User
+
+	err := ur.inListingTx(c, func(q *db.Queries) error {
+		var err error
+		dbUsers, err = q.ListUsersAfter(c, params)
+		return err
+	})
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	users := make([]domain.User, 0, len(dbUsers))
+
+	for _, u := range dbUsers {
+		users = append(users, toDomainUser(u))
 	}
 
 	return users, nil
@@ -70,58 +196,407 @@ func (ur *UserRepository) GetById(c context.Context, id uuid.UUID) (domain.User,
 	dbUser, err := ur.queries.GetUser(c, ToPgUUID(id))
 
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, translateError(err)
 	}
 
-	user := domain.User{
-		UserId:    ToUUIDFromPgUUID(dbUser.UserID),
-		FirstName: dbUser.FirstName,
-		LastName:  dbUser.LastName,
-		Email:     dbUser.Email,
-		Phone:     dbUser.Phone,
-		Age:       int(dbUser.Age),
-		Status:    domain.UserStatus(dbUser.Status),
+	return toDomainUser(dbUser), nil
+}
+
+func (ur *UserRepository) Update(c context.Context, id uuid.UUID, user *domain.User) (db.UpdateUserRow, error) {
+	var updated db.UpdateUserRow
+
+	err := ur.write(c, id, domain.UserUpdated, user.Status, "", func(q *db.Queries) error {
+		var err error
+		updated, err = q.UpdateUser(c, db.UpdateUserParams{
+			UserID:          ToPgUUID(id),
+			FirstName:       toPgText(user.FirstName),
+			LastName:        toPgText(user.LastName),
+			Email:           toPgText(user.Email),
+			Phone:           toPgText(user.Phone),
+			DateOfBirth:     toPgDate(user.DateOfBirth),
+			Status:          toPgText(string(user.Status)),
+			ExpectedVersion: toPgInt4(nonZero(user.Version)),
+			UpdatedBy:       toPgText(domain.ActorFrom(c)),
+		})
+		return err
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.UpdateUserRow{}, ur.missedWriteError(c, id, err)
 	}
 
-	return user, nil
+	return updated, translateError(err)
 }
 
-func (ur *UserRepository) Update(c context.Context, id uuid.UUID, user *domain.User) (db.UpdateUserRow, error) {
-	retrived, retError := ur.GetById(c, id)
+func (ur *UserRepository) Replace(c context.Context, user *domain.User) (db.UpdateUserRow, error) {
+	var replaced db.ReplaceUserRow
+
+	err := ur.write(c, user.UserId, domain.UserUpdated, user.Status, "", func(q *db.Queries) error {
+		var err error
+		replaced, err = q.ReplaceUser(c, db.ReplaceUserParams{
+			UserID:          ToPgUUID(user.UserId),
+			FirstName:       user.FirstName,
+			LastName:        user.LastName,
+			Email:           user.Email,
+			Phone:           user.Phone,
+			DateOfBirth:     toPgDate(user.DateOfBirth),
+			Status:          string(user.Status),
+			ExpectedVersion: int32(user.Version),
+			UpdatedBy:       toPgText(domain.ActorFrom(c)),
+		})
+		return err
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.UpdateUserRow{}, ur.missedWriteError(c, user.UserId, err)
+	}
+
+	return db.UpdateUserRow(replaced), translateError(err)
+}
 
-	updateDbEntity(&retrived, user)
+func (ur *UserRepository) ChangeStatus(c context.Context, id uuid.UUID, status domain.UserStatus, reason string, expectedVersion int) (db.UpdateUserRow, error) {
+	var changed db.UpdateUserStatusRow
 
-	if retError != nil {
-		return db.UpdateUserRow{}, retError
+	err := ur.write(c, id, domain.UserUpdated, status, reason, func(q *db.Queries) error {
+		var err error
+		changed, err = q.UpdateUserStatus(c, db.UpdateUserStatusParams{
+			UserID:          ToPgUUID(id),
+			Status:          string(status),
+			ExpectedVersion: toPgInt4(nonZero(expectedVersion)),
+			UpdatedBy:       toPgText(domain.ActorFrom(c)),
+		})
+		return err
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.UpdateUserRow{}, ur.missedWriteError(c, id, err)
 	}
 
-	createdd, err := ur.queries.UpdateUser(c, db.UpdateUserParams{
-		UserID:    ToPgUUID(id),
-		FirstName: retrived.FirstName,
-		LastName:  retrived.LastName,
-		Email:     retrived.Email,
-		Phone:     retrived.Phone,
-		Age:       int32(retrived.Age),
-		Status:    int32(retrived.Status),
+	return db.UpdateUserRow(changed), translateError(err)
+}
+
+// write runs fn in a transaction holding the user's row lock and records the
+// revision it produces in the user's history, along with the events telling
+// other services about it in the outbox and notifying live streams on commit.
+// When status is set, the lifecycle must allow the user to move to it, and a
+// move is recorded along with reason.
+func (ur *UserRepository) write(c context.Context, id uuid.UUID, op domain.UserOperation, status domain.UserStatus, reason string, fn func(q *db.Queries) error) error {
+	return ur.inTx(c, func(q *db.Queries) error {
+		var before domain.User
+		if op != domain.UserCreated {
+			current, err := q.GetUserForUpdate(c, ToPgUUID(id))
+			if err != nil {
+				return err
+			}
+			// Only restoring applies to deleted users.
+			if current.DeletedAt.Valid != (op == domain.UserRestored) {
+				return pgx.ErrNoRows
+			}
+			before = toDomainUser(current)
+
+			if status != "" {
+				if err := before.Status.CheckTransition(status); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := fn(q); err != nil {
+			return err
+		}
+
+		written, err := q.GetUserForUpdate(c, ToPgUUID(id))
+		if err != nil {
+			return err
+		}
+
+		after := toDomainUser(written)
+
+		changes, err := json.Marshal(domain.DiffUsers(before, after))
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateUserRevision(c, db.CreateUserRevisionParams{
+			UserID:    ToPgUUID(id),
+			Revision:  written.Version,
+			Operation: string(op),
+			Changes:   changes,
+			ChangedBy: toPgText(domain.ActorFrom(c)),
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range domain.NewUserEvents(op, before, after, reason) {
+			payload, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			err = q.CreateOutboxEvent(c, db.CreateOutboxEventParams{
+				EventID:     ToPgUUID(event.ID),
+				EventType:   string(event.Type),
+				AggregateID: ToPgUUID(event.UserId),
+				Payload:     payload,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := q.NotifyUserEvent(c, string(payload)); err != nil {
+				return err
+			}
+		}
+
+		if op == domain.UserCreated || status == "" || before.Status == status {
+			return nil
+		}
+
+		return q.CreateUserStatusChange(c, db.CreateUserStatusChangeParams{
+			UserID:     ToPgUUID(id),
+			FromStatus: string(before.Status),
+			ToStatus:   string(status),
+			Reason:     toPgText(reason),
+			ChangedBy:  toPgText(domain.ActorFrom(c)),
+		})
+	})
+}
+
+// inTx runs fn with queries bound to a new transaction, committing it if fn
+// succeeds and rolling it back otherwise.
+func (ur *UserRepository) inTx(c context.Context, fn func(q *db.Queries) error) error {
+	tx, err := ur.connectionPool.Begin(c)
+	if err != nil {
+		return err
+	}
+	// Rolling back after a commit is a no-op.
+	defer func() { _ = tx.Rollback(c) }()
+
+	if err := fn(ur.queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(c)
+}
+
+// inListingTx runs a listing query in fn with custom plans forced for its
+// transaction only, rather than for every query on the pool.
+func (ur *UserRepository) inListingTx(c context.Context, fn func(q *db.Queries) error) error {
+	return ur.inTx(c, func(q *db.Queries) error {
+		if err := q.UseCustomPlans(c); err != nil {
+			return err
+		}
+		return fn(q)
+	})
+}
+
+func (ur *UserRepository) Delete(c context.Context, id uuid.UUID, expectedVersion int) (uuid.UUID, error) {
+	var deletedUserId pgtype.UUID
+
+	err := ur.write(c, id, domain.UserDeleted, "", "", func(q *db.Queries) error {
+		var err error
+		deletedUserId, err = q.DeleteUser(c, db.DeleteUserParams{
+			UserID:          ToPgUUID(id),
+			ExpectedVersion: toPgInt4(nonZero(expectedVersion)),
+			UpdatedBy:       toPgText(domain.ActorFrom(c)),
+		})
+		return err
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ur.missedWriteError(c, id, err)
+	}
+
+	if err != nil {
+		return uuid.Nil, translateError(err)
+	}
+
+	return ToUUIDFromPgUUID(deletedUserId), nil
+}
+
+func (ur *UserRepository) Restore(c context.Context, id uuid.UUID) (db.UpdateUserRow, error) {
+	var restored db.RestoreUserRow
+
+	err := ur.write(c, id, domain.UserRestored, "", "", func(q *db.Queries) error {
+		var err error
+		restored, err = q.RestoreUser(c, db.RestoreUserParams{
+			UserID:    ToPgUUID(id),
+			UpdatedBy: toPgText(domain.ActorFrom(c)),
+		})
+		return err
 	})
 
-	created := db.UpdateUserRow{
-		UserID: createdd.UserID,
-		Email:  createdd.Email,
-		Status: createdd.Status,
+	if errors.Is(err, pgx.ErrNoRows) {
+		exists, existsErr := ur.queries.UserExists(c, ToPgUUID(id))
+		if existsErr != nil {
+			return db.UpdateUserRow{}, translateError(existsErr)
+		}
+		if exists {
+			return db.UpdateUserRow{}, domain.NewConflictError("user is not deleted", err)
+		}
+		return db.UpdateUserRow{}, domain.NewNotFoundError("user not found", err)
 	}
 
-	return created, err
+	return db.UpdateUserRow(restored), translateError(err)
+}
+
+func (ur *UserRepository) Purge(c context.Context, id uuid.UUID) error {
+	_, err := ur.queries.PurgeUser(c, ToPgUUID(id))
+	return translateError(err)
+}
+
+func (ur *UserRepository) PurgeDeleted(c context.Context, before time.Time) (int64, error) {
+	purged, err := ur.queries.PurgeDeletedUsers(c, pgtype.Timestamptz{Time: before, Valid: true})
+	return purged, translateError(err)
 }
 
-func (ur *UserRepository) Delete(c context.Context, id uuid.UUID) (uuid.UUID, error) {
-	deletedUserId, err := ur.queries.DeleteUser(c, ToPgUUID(id))
+func (ur *UserRepository) CountByStatus(c context.Context) (map[domain.UserStatus]int64, error) {
+	rows, err := ur.queries.CountUsersByStatus(c)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	counts := make(map[domain.UserStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[domain.UserStatus(row.Status)] = row.Users
+	}
+
+	return counts, nil
+}
 
+func (ur *UserRepository) GetHistory(c context.Context, id uuid.UUID) ([]domain.UserRevision, error) {
+	rows, err := ur.queries.ListUserRevisions(c, ToPgUUID(id))
 	if err != nil {
-		return uuid.New(), err
+		return nil, translateError(err)
+	}
+
+	if len(rows) == 0 {
+		return nil, domain.NewNotFoundError("user not found", nil)
+	}
+
+	return toDomainRevisions(rows)
+}
+
+func (ur *UserRepository) GetRevision(c context.Context, id uuid.UUID, revision int) (domain.User, error) {
+	rows, err := ur.queries.ListUserRevisionsUpTo(c, db.ListUserRevisionsUpToParams{
+		UserID:   ToPgUUID(id),
+		Revision: toPgInt4(&revision),
+	})
+	if err != nil {
+		return domain.User{}, translateError(err)
+	}
+
+	revisions, err := toDomainRevisions(rows)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return revisionOf(revisions, revision)
+}
+
+func (ur *UserRepository) GetAsOf(c context.Context, id uuid.UUID, at time.Time) (domain.User, error) {
+	rows, err := ur.queries.ListUserRevisionsUpTo(c, db.ListUserRevisionsUpToParams{
+		UserID:    ToPgUUID(id),
+		ChangedAt: toPgTimestamptz(&at),
+	})
+	if err != nil {
+		return domain.User{}, translateError(err)
+	}
+
+	revisions, err := toDomainRevisions(rows)
+	if err != nil {
+		return domain.User{}, err
 	}
 
-	return ToUUIDFromPgUUID(deletedUserId), err
+	return asOf(revisions, at)
+}
+
+// revisionOf rebuilds a user from its revisions up to and including
+// revision.
+func revisionOf(revisions []domain.UserRevision, revision int) (domain.User, error) {
+	if len(revisions) == 0 || revisions[len(revisions)-1].Revision != revision {
+		return domain.User{}, domain.NewNotFoundError("revision not found", nil)
+	}
+
+	return replayUser(revisions, revisions[len(revisions)-1].ChangedAt)
+}
+
+// asOf rebuilds a user from the revisions made up to at.
+func asOf(revisions []domain.UserRevision, at time.Time) (domain.User, error) {
+	if len(revisions) == 0 {
+		return domain.User{}, domain.NewNotFoundError("user did not exist at that time", nil)
+	}
+
+	return replayUser(revisions, at)
+}
+
+// replayUser rebuilds a user from its history, deriving its age on the day it
+// is being looked at.
+func replayUser(revisions []domain.UserRevision, at time.Time) (domain.User, error) {
+	user, deleted := domain.ReplayUser(revisions)
+	if deleted {
+		return domain.User{}, domain.NewNotFoundError("user was deleted at that time", nil)
+	}
+
+	user.Age = domain.AgeOn(user.DateOfBirth, at)
+
+	return user, nil
+}
+
+func toDomainRevisions(rows []db.UserHistory) ([]domain.UserRevision, error) {
+	revisions := make([]domain.UserRevision, 0, len(rows))
+
+	for _, row := range rows {
+		var changes domain.UserChanges
+		if err := json.Unmarshal(row.Changes, &changes); err != nil {
+			return nil, fmt.Errorf("decoding revision %d of user: %w", row.Revision, err)
+		}
+
+		revisions = append(revisions, domain.UserRevision{
+			UserId:    ToUUIDFromPgUUID(row.UserID),
+			Revision:  int(row.Revision),
+			Operation: domain.UserOperation(row.Operation),
+			Changes:   changes,
+			ChangedBy: row.ChangedBy.String,
+			ChangedAt: row.ChangedAt.Time,
+		})
+	}
+
+	return revisions, nil
+}
+
+// missedWriteError explains why a conditional write matched no rows: either
+// the user does not exist or its version no longer matches.
+func (ur *UserRepository) missedWriteError(c context.Context, id uuid.UUID, err error) error {
+	exists, existsErr := ur.queries.UserExists(c, ToPgUUID(id))
+	if existsErr != nil {
+		return translateError(existsErr)
+	}
+
+	if !exists {
+		return domain.NewNotFoundError("user not found", err)
+	}
+
+	return domain.NewPreconditionFailedError("user has been modified since it was read", err)
+}
+
+func toDomainUser(u db.User) domain.User {
+	return domain.User{
+		UserId:      ToUUIDFromPgUUID(u.UserID),
+		FirstName:   u.FirstName,
+		LastName:    u.LastName,
+		Email:       u.Email,
+		Phone:       u.Phone,
+		DateOfBirth: u.DateOfBirth.Time,
+		Age:         domain.AgeOn(u.DateOfBirth.Time, time.Now()),
+		Status:      domain.UserStatus(u.Status),
+		Version:     int(u.Version),
+		CreatedAt:   u.CreatedAt.Time,
+		UpdatedAt:   u.UpdatedAt.Time,
+		CreatedBy:   u.CreatedBy.String,
+		UpdatedBy:   u.UpdatedBy.String,
+	}
 }
 
 func ToPgUUID(id uuid.UUID) pgtype.UUID {
@@ -138,28 +613,54 @@ func ToUUIDFromPgUUID(id pgtype.UUID) uuid.UUID {
 	return id.Bytes
 }
 
-func updateDbEntity(retrieved *domain.User, current *domain.User) {
-	if current.FirstName != "" {
-		retrieved.FirstName = current.FirstName
-	}
+func toPgText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
 
-	if current.LastName != "" {
-		retrieved.LastName = current.LastName
+func nonZero(value int) *int {
+	if value == 0 {
+		return nil
 	}
+	return &value
+}
 
-	if current.Email != "" {
-		retrieved.Email = current.Email
+// sortDescending reports whether the sort column is read in descending order.
+// Ages are sorted by date of birth, which runs the other way.
+func sortDescending(query domain.UserQuery) bool {
+	return query.SortDesc != (query.SortBy == domain.UserSortByAge)
+}
+
+// toPgDate keeps only the calendar date of value; the zero time becomes NULL.
+func toPgDate(value time.Time) pgtype.Date {
+	if value.IsZero() {
+		return pgtype.Date{}
 	}
+	year, month, day := value.Date()
+	return pgtype.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+}
 
-	if current.Phone != "" {
-		retrieved.Phone = current.Phone
+func toPgTimestamptz(value *time.Time) pgtype.Timestamptz {
+	if value == nil {
+		return pgtype.Timestamptz{}
 	}
+	return pgtype.Timestamptz{Time: *value, Valid: true}
+}
 
-	if current.Age != 0 {
-		retrieved.Age = current.Age
+func toPgInt4(value *int) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
 	}
+	return pgtype.Int4{Int32: int32(*value), Valid: true}
+}
 
-	if current.Status != 0 {
-		retrieved.Status = current.Status
+// toPgPrefixPattern builds a case-insensitive LIKE pattern that matches values
+// starting with prefix, escaping any wildcards the caller supplied.
+func toPgPrefixPattern(prefix string) pgtype.Text {
+	if prefix == "" {
+		return pgtype.Text{}
 	}
+
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(prefix))
+
+	return pgtype.Text{String: escaped + "%", Valid: true}
 }
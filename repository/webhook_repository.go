@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-management/domain"
+	"user-management/internal/db"
+	"user-management/internal/webhook"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepository struct {
+	connectionPool *pgxpool.Pool
+	queries        *db.Queries
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) webhook.Store {
+	return &WebhookRepository{
+		connectionPool: pool,
+		queries:        db.New(pool),
+	}
+}
+
+func (wr *WebhookRepository) CreateSubscription(c context.Context, subscription webhook.Subscription) (webhook.Subscription, error) {
+	created, err := wr.queries.CreateWebhookSubscription(c, db.CreateWebhookSubscriptionParams{
+		ID:         ToPgUUID(subscription.ID),
+		Url:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Secret:     subscription.Secret,
+	})
+	if err != nil {
+		return webhook.Subscription{}, translateError(err)
+	}
+
+	return toSubscription(created), nil
+}
+
+func (wr *WebhookRepository) GetSubscription(c context.Context, id uuid.UUID) (webhook.Subscription, error) {
+	subscription, err := wr.queries.GetWebhookSubscription(c, ToPgUUID(id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return webhook.Subscription{}, domain.NewNotFoundError("webhook subscription not found", err)
+	}
+	if err != nil {
+		return webhook.Subscription{}, translateError(err)
+	}
+
+	return toSubscription(subscription), nil
+}
+
+func (wr *WebhookRepository) ListSubscriptions(c context.Context) ([]webhook.Subscription, error) {
+	rows, err := wr.queries.ListWebhookSubscriptions(c)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	subscriptions := make([]webhook.Subscription, 0, len(rows))
+	for _, row := range rows {
+		subscriptions = append(subscriptions, toSubscription(row))
+	}
+
+	return subscriptions, nil
+}
+
+func (wr *WebhookRepository) DeleteSubscription(c context.Context, id uuid.UUID) error {
+	deleted, err := wr.queries.DeleteWebhookSubscription(c, ToPgUUID(id))
+	if err != nil {
+		return translateError(err)
+	}
+
+	if deleted == 0 {
+		return domain.NewNotFoundError("webhook subscription not found", nil)
+	}
+
+	return nil
+}
+
+func (wr *WebhookRepository) Enqueue(c context.Context, eventID uuid.UUID, eventType string, payload []byte) error {
+	_, err := wr.queries.EnqueueWebhookDeliveries(c, db.EnqueueWebhookDeliveriesParams{
+		EventID:   ToPgUUID(eventID),
+		EventType: eventType,
+		Payload:   payload,
+	})
+	return translateError(err)
+}
+
+func (wr *WebhookRepository) ClaimDue(c context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	rows, err := wr.queries.ClaimDueWebhookDeliveries(c, db.ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: lease.Seconds(),
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(rows))
+	for _, row := range rows {
+		delivery := toDelivery(db.WebhookDelivery{
+			ID:             row.ID,
+			SubscriptionID: row.SubscriptionID,
+			EventID:        row.EventID,
+			EventType:      row.EventType,
+			Payload:        row.Payload,
+			Status:         row.Status,
+			Attempts:       row.Attempts,
+			NextAttemptAt:  row.NextAttemptAt,
+			LastError:      row.LastError,
+			CreatedAt:      row.CreatedAt,
+			DeliveredAt:    row.DeliveredAt,
+		})
+		delivery.URL = row.Url
+		delivery.Secret = row.Secret
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (wr *WebhookRepository) RecordAttempt(c context.Context, delivery webhook.Delivery, attempt webhook.Attempt) error {
+	tx, err := wr.connectionPool.Begin(c)
+	if err != nil {
+		return translateError(err)
+	}
+	// Rolling back after a commit is a no-op.
+	defer func() { _ = tx.Rollback(c) }()
+
+	q := wr.queries.WithTx(tx)
+
+	err = q.CreateWebhookDeliveryAttempt(c, db.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:  attempt.DeliveryID,
+		AttemptedAt: pgtype.Timestamptz{Time: attempt.AttemptedAt, Valid: true},
+		StatusCode:  pgtype.Int4{Int32: int32(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+		Error:       toPgText(attempt.Error),
+		DurationMs:  int32(attempt.Duration.Milliseconds()),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	err = q.UpdateWebhookDelivery(c, db.UpdateWebhookDeliveryParams{
+		ID:            delivery.ID,
+		Status:        string(delivery.Status),
+		Attempts:      int32(delivery.Attempts),
+		NextAttemptAt: pgtype.Timestamptz{Time: delivery.NextAttemptAt, Valid: true},
+		LastError:     toPgText(delivery.LastError),
+		DeliveredAt:   pgtype.Timestamptz{Time: delivery.DeliveredAt, Valid: !delivery.DeliveredAt.IsZero()},
+	})
+	if err != nil {
+		return translateError(err)
+	}
+
+	return translateError(tx.Commit(c))
+}
+
+func (wr *WebhookRepository) ListDeliveries(c context.Context, subscriptionID uuid.UUID, status webhook.DeliveryStatus, afterID int64, limit int) ([]webhook.Delivery, error) {
+	rows, err := wr.queries.ListWebhookDeliveries(c, db.ListWebhookDeliveriesParams{
+		SubscriptionID: ToPgUUID(subscriptionID),
+		AfterID:        afterID,
+		Status:         toPgText(string(status)),
+		PageLimit:      int32(limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	deliveries := make([]webhook.Delivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, toDelivery(row))
+	}
+
+	return deliveries, nil
+}
+
+func (wr *WebhookRepository) ListAttempts(c context.Context, subscriptionID uuid.UUID, deliveryID int64) ([]webhook.Attempt, error) {
+	_, err := wr.queries.GetWebhookDelivery(c, db.GetWebhookDeliveryParams{
+		ID:             deliveryID,
+		SubscriptionID: ToPgUUID(subscriptionID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.NewNotFoundError("webhook delivery not found", err)
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := wr.queries.ListWebhookDeliveryAttempts(c, db.ListWebhookDeliveryAttemptsParams{
+		SubscriptionID: ToPgUUID(subscriptionID),
+		DeliveryID:     deliveryID,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	attempts := make([]webhook.Attempt, 0, len(rows))
+	for _, row := range rows {
+		attempts = append(attempts, webhook.Attempt{
+			ID:          row.ID,
+			DeliveryID:  row.DeliveryID,
+			AttemptedAt: row.AttemptedAt.Time,
+			StatusCode:  int(row.StatusCode.Int32),
+			Error:       row.Error.String,
+			Duration:    time.Duration(row.DurationMs) * time.Millisecond,
+		})
+	}
+
+	return attempts, nil
+}
+
+func (wr *WebhookRepository) Replay(c context.Context, subscriptionID uuid.UUID, deliveryID int64) (webhook.Delivery, error) {
+	replayed, err := wr.queries.ReplayWebhookDelivery(c, db.ReplayWebhookDeliveryParams{
+		ID:             deliveryID,
+		SubscriptionID: ToPgUUID(subscriptionID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return webhook.Delivery{}, domain.NewNotFoundError("webhook delivery not found", err)
+	}
+	if err != nil {
+		return webhook.Delivery{}, translateError(err)
+	}
+
+	return toDelivery(replayed), nil
+}
+
+func toSubscription(s db.WebhookSubscription) webhook.Subscription {
+	return webhook.Subscription{
+		ID:         ToUUIDFromPgUUID(s.ID),
+		URL:        s.Url,
+		EventTypes: s.EventTypes,
+		Secret:     s.Secret,
+		CreatedAt:  s.CreatedAt.Time,
+	}
+}
+
+func toDelivery(d db.WebhookDelivery) webhook.Delivery {
+	return webhook.Delivery{
+		ID:             d.ID,
+		SubscriptionID: ToUUIDFromPgUUID(d.SubscriptionID),
+		EventID:        ToUUIDFromPgUUID(d.EventID),
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         webhook.DeliveryStatus(d.Status),
+		Attempts:       int(d.Attempts),
+		NextAttemptAt:  d.NextAttemptAt.Time,
+		LastError:      d.LastError.String,
+		CreatedAt:      d.CreatedAt.Time,
+		DeliveredAt:    d.DeliveredAt.Time,
+	}
+}
@@ -0,0 +1,464 @@
+// Package conformance holds test suites shared by every implementation of a
+// domain interface, so that they cannot drift apart.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserRepository checks that a domain.UserRepository behaves the way the
+// API relies on. newRepository must return a repository holding no users
+// each time it is called. Names are chosen so that any collation sorts them
+// the same way.
+func TestUserRepository(t *testing.T, newRepository func(t *testing.T) domain.UserRepository) {
+	ctx := context.Background()
+	today := time.Now()
+
+	newUser := func(firstName string, age int) domain.User {
+		return domain.User{
+			UserId:      uuid.New(),
+			FirstName:   firstName,
+			LastName:    "Tester",
+			Email:       fmt.Sprintf("%s.%s@example.com", strings.ToLower(firstName), uuid.NewString()[:8]),
+			Phone:       "1234567890",
+			DateOfBirth: time.Date(today.Year()-age, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Status:      domain.UserStatusActive,
+		}
+	}
+
+	create := func(t *testing.T, repository domain.UserRepository, user domain.User) domain.User {
+		t.Helper()
+		_, err := repository.Create(ctx, &user)
+		require.NoError(t, err)
+		return user
+	}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("Ada", 36)
+
+		created, err := repository.Create(domain.WithActor(ctx, "provisioner"), &user)
+		require.NoError(t, err)
+		assert.Equal(t, user.Email, created.Email)
+		assert.Equal(t, int32(1), created.Version)
+		assert.Equal(t, "provisioner", created.CreatedBy.String)
+		assert.Equal(t, "provisioner", created.UpdatedBy.String)
+
+		found, err := repository.GetById(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, user.FirstName, found.FirstName)
+		assert.Equal(t, user.Email, found.Email)
+		assert.Equal(t, domain.UserStatusActive, found.Status)
+		assert.Equal(t, 36, found.Age)
+		assert.Equal(t, 1, found.Version)
+		assert.Equal(t, "provisioner", found.CreatedBy)
+		assert.False(t, found.CreatedAt.IsZero())
+	})
+
+	t.Run("CreateRejectsConflictsAndInvalidUsers", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		sameEmail := newUser("Grace", 40)
+		sameEmail.Email = user.Email
+		_, err := repository.Create(ctx, &sameEmail)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		sameID := newUser("Grace", 40)
+		sameID.UserId = user.UserId
+		_, err = repository.Create(ctx, &sameID)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		unknownStatus := newUser("Grace", 40)
+		unknownStatus.Status = "retired"
+		_, err = repository.Create(ctx, &unknownStatus)
+		assert.Equal(t, domain.ErrorKindValidation, domain.ErrorKindOf(err))
+
+		noDateOfBirth := newUser("Grace", 40)
+		noDateOfBirth.DateOfBirth = time.Time{}
+		_, err = repository.Create(ctx, &noDateOfBirth)
+		assert.Equal(t, domain.ErrorKindValidation, domain.ErrorKindOf(err))
+	})
+
+	t.Run("GetMissingUser", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.GetById(ctx, uuid.New())
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("UpdateChangesOnlyGivenFields", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		updated, err := repository.Update(domain.WithActor(ctx, "editor"), user.UserId, &domain.User{LastName: "Lovelace", Version: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), updated.Version)
+		assert.Equal(t, "editor", updated.UpdatedBy.String)
+
+		found, err := repository.GetById(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", found.FirstName)
+		assert.Equal(t, "Lovelace", found.LastName)
+		assert.Equal(t, user.Email, found.Email)
+		assert.Equal(t, user.DateOfBirth.Format(time.DateOnly), found.DateOfBirth.Format(time.DateOnly))
+		assert.Equal(t, 2, found.Version)
+	})
+
+	t.Run("UpdateFailures", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+		other := create(t, repository, newUser("Grace", 40))
+
+		_, err := repository.Update(ctx, uuid.New(), &domain.User{FirstName: "Nobody"})
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		_, err = repository.Update(ctx, user.UserId, &domain.User{FirstName: "Stale", Version: 7})
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		_, err = repository.Update(ctx, user.UserId, &domain.User{Email: other.Email})
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		_, err = repository.Update(ctx, user.UserId, &domain.User{Status: domain.UserStatusPending})
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		// Nothing above was written.
+		found, err := repository.GetById(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.Version)
+		assert.Equal(t, user.Email, found.Email)
+	})
+
+	t.Run("ReplaceOverwritesEveryField", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		replacement := newUser("Augusta", 37)
+		replacement.UserId = user.UserId
+		replacement.Phone = ""
+
+		_, err := repository.Replace(ctx, &replacement)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		replacement.Version = 1
+		replaced, err := repository.Replace(ctx, &replacement)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), replaced.Version)
+
+		found, err := repository.GetById(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, "Augusta", found.FirstName)
+		assert.Equal(t, replacement.Email, found.Email)
+		assert.Equal(t, "", found.Phone)
+		assert.Equal(t, 37, found.Age)
+	})
+
+	t.Run("ChangeStatusFollowsLifecycle", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		changed, err := repository.ChangeStatus(ctx, user.UserId, domain.UserStatusSuspended, "fraud review", 1)
+		require.NoError(t, err)
+		assert.Equal(t, string(domain.UserStatusSuspended), changed.Status)
+
+		_, err = repository.ChangeStatus(ctx, user.UserId, domain.UserStatusLocked, "", 0)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		_, err = repository.ChangeStatus(ctx, user.UserId, domain.UserStatusActive, "", 1)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		_, err = repository.ChangeStatus(ctx, uuid.New(), domain.UserStatusActive, "", 0)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("DeleteHidesUserUntilRestored", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		_, err := repository.Delete(ctx, user.UserId, 2)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		deletedID, err := repository.Delete(ctx, user.UserId, 1)
+		require.NoError(t, err)
+		assert.Equal(t, user.UserId, deletedID)
+
+		_, err = repository.GetById(ctx, user.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		_, err = repository.Update(ctx, user.UserId, &domain.User{FirstName: "Ghost"})
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		_, err = repository.Delete(ctx, user.UserId, 0)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		restored, err := repository.Restore(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), restored.Version)
+
+		_, err = repository.Restore(ctx, user.UserId)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		_, err = repository.Restore(ctx, uuid.New())
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		found, err := repository.GetById(ctx, user.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, 3, found.Version)
+	})
+
+	t.Run("DeletedUsersFreeTheirEmail", func(t *testing.T) {
+		repository := newRepository(t)
+		user := create(t, repository, newUser("Ada", 36))
+
+		_, err := repository.Delete(ctx, user.UserId, 0)
+		require.NoError(t, err)
+
+		replacement := newUser("Grace", 40)
+		replacement.Email = user.Email
+		create(t, repository, replacement)
+
+		_, err = repository.Restore(ctx, user.UserId)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		repository := newRepository(t)
+		live := create(t, repository, newUser("Ada", 36))
+		deleted := create(t, repository, newUser("Grace", 40))
+
+		_, err := repository.Delete(ctx, deleted.UserId, 0)
+		require.NoError(t, err)
+
+		purged, err := repository.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repository.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		_, err = repository.GetHistory(ctx, deleted.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		require.NoError(t, repository.Purge(ctx, live.UserId))
+
+		_, err = repository.GetById(ctx, live.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		err = repository.Purge(ctx, live.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("CountByStatus", func(t *testing.T) {
+		repository := newRepository(t)
+
+		counts, err := repository.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		create(t, repository, newUser("Ada", 36))
+		suspended := create(t, repository, newUser("Grace", 40))
+		deleted := create(t, repository, newUser("Alan", 41))
+
+		pending := newUser("Edsger", 50)
+		pending.Status = domain.UserStatusPending
+		create(t, repository, pending)
+
+		_, err = repository.ChangeStatus(ctx, suspended.UserId, domain.UserStatusSuspended, "", 0)
+		require.NoError(t, err)
+		_, err = repository.Delete(ctx, deleted.UserId, 0)
+		require.NoError(t, err)
+
+		counts, err = repository.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[domain.UserStatus]int64{
+			domain.UserStatusActive:    1,
+			domain.UserStatusSuspended: 1,
+			domain.UserStatusPending:   1,
+		}, counts)
+	})
+
+	t.Run("ListFiltersAndSorts", func(t *testing.T) {
+		repository := newRepository(t)
+		create(t, repository, newUser("Ada", 36))
+		alan := create(t, repository, newUser("Alan", 41))
+		grace := create(t, repository, newUser("Grace", 45))
+		hidden := create(t, repository, newUser("Hidden", 30))
+
+		_, err := repository.ChangeStatus(ctx, grace.UserId, domain.UserStatusSuspended, "", 0)
+		require.NoError(t, err)
+		_, err = repository.Delete(ctx, hidden.UserId, 0)
+		require.NoError(t, err)
+
+		firstNames := func(users []domain.User) []string {
+			names := make([]string, 0, len(users))
+			for _, user := range users {
+				names = append(names, user.FirstName)
+			}
+			return names
+		}
+
+		list := func(query domain.UserQuery) ([]string, int64) {
+			t.Helper()
+			if query.Limit == 0 {
+				query.Limit = 10
+			}
+			users, total, err := repository.GetAll(ctx, query)
+			require.NoError(t, err)
+			return firstNames(users), total
+		}
+
+		names, total := list(domain.UserQuery{SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Ada", "Alan", "Grace"}, names)
+		assert.Equal(t, int64(3), total)
+
+		names, _ = list(domain.UserQuery{SortBy: domain.UserSortByFirstName, SortDesc: true})
+		assert.Equal(t, []string{"Grace", "Alan", "Ada"}, names)
+
+		names, _ = list(domain.UserQuery{SortBy: domain.UserSortByAge, SortDesc: true})
+		assert.Equal(t, []string{"Grace", "Alan", "Ada"}, names)
+
+		names, total = list(domain.UserQuery{SortBy: domain.UserSortByFirstName, Limit: 1, Offset: 1})
+		assert.Equal(t, []string{"Alan"}, names)
+		assert.Equal(t, int64(3), total)
+
+		suspended := domain.UserStatusSuspended
+		names, total = list(domain.UserQuery{Filter: domain.UserFilter{Status: &suspended}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Grace"}, names)
+		assert.Equal(t, int64(1), total)
+
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{NamePrefix: "a"}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Ada", "Alan"}, names)
+
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{NamePrefix: "TEST"}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Ada", "Alan", "Grace"}, names)
+
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{Email: alan.Email}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Alan"}, names)
+
+		minAge, maxAge := 40, 44
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{MinAge: &minAge, MaxAge: &maxAge}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Alan"}, names)
+
+		future := time.Now().Add(time.Hour)
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{CreatedAfter: &future}, SortBy: domain.UserSortByFirstName})
+		assert.Empty(t, names)
+
+		names, _ = list(domain.UserQuery{Filter: domain.UserFilter{CreatedBefore: &future}, SortBy: domain.UserSortByFirstName})
+		assert.Equal(t, []string{"Ada", "Alan", "Grace"}, names)
+	})
+
+	t.Run("ListAfterCursorVisitsEveryUserOnce", func(t *testing.T) {
+		repository := newRepository(t)
+		for i, name := range []string{"Ada", "Alan", "Barbara", "Grace", "Hedy"} {
+			user := newUser(name, 30+i%2)
+			user.LastName = "Same"
+			create(t, repository, user)
+		}
+
+		sorts := []domain.UserSortField{
+			domain.UserSortByFirstName,
+			domain.UserSortByLastName,
+			domain.UserSortByEmail,
+			domain.UserSortByAge,
+			domain.UserSortByDateOfBirth,
+			domain.UserSortByCreatedAt,
+			domain.UserSortByUpdatedAt,
+		}
+
+		for _, sortBy := range sorts {
+			for _, desc := range []bool{false, true} {
+				query := domain.UserQuery{SortBy: sortBy, SortDesc: desc, Limit: 2}
+
+				expected, _, err := repository.GetAll(ctx, domain.UserQuery{SortBy: sortBy, SortDesc: desc, Limit: 10})
+				require.NoError(t, err)
+
+				page, _, err := repository.GetAll(ctx, query)
+				require.NoError(t, err)
+
+				var visited []domain.User
+				for len(page) > 0 {
+					visited = append(visited, page...)
+					page, err = repository.GetAllAfter(ctx, query, domain.NewUserCursor(page[len(page)-1], sortBy, desc))
+					require.NoError(t, err)
+				}
+
+				assert.Equal(t, userIDs(expected), userIDs(visited), "sort %s desc %v", sortBy, desc)
+			}
+		}
+	})
+
+	t.Run("ListAfterInvalidCursor", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.GetAllAfter(ctx, domain.UserQuery{SortBy: domain.UserSortByCreatedAt, Limit: 2}, domain.UserCursor{LastValue: "yesterday"})
+		assert.Equal(t, domain.ErrorKindValidation, domain.ErrorKindOf(err))
+	})
+
+	t.Run("History", func(t *testing.T) {
+		repository := newRepository(t)
+
+		beforeCreate := time.Now()
+		time.Sleep(10 * time.Millisecond)
+
+		user := create(t, repository, newUser("Ada", 36))
+		time.Sleep(10 * time.Millisecond)
+		afterCreate := time.Now()
+		time.Sleep(10 * time.Millisecond)
+
+		_, err := repository.Update(domain.WithActor(ctx, "editor"), user.UserId, &domain.User{FirstName: "Augusta"})
+		require.NoError(t, err)
+		_, err = repository.Delete(ctx, user.UserId, 0)
+		require.NoError(t, err)
+
+		revisions, err := repository.GetHistory(ctx, user.UserId)
+		require.NoError(t, err)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, domain.UserCreated, revisions[0].Operation)
+		assert.Equal(t, domain.FieldChange{From: "", To: "Ada"}, revisions[0].Changes["firstName"])
+		assert.Equal(t, domain.UserUpdated, revisions[1].Operation)
+		assert.Equal(t, domain.UserChanges{"firstName": {From: "Ada", To: "Augusta"}}, revisions[1].Changes)
+		assert.Equal(t, "editor", revisions[1].ChangedBy)
+		assert.Equal(t, domain.UserDeleted, revisions[2].Operation)
+		assert.Equal(t, 3, revisions[2].Revision)
+
+		original, err := repository.GetRevision(ctx, user.UserId, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", original.FirstName)
+		assert.Equal(t, user.Email, original.Email)
+		assert.Equal(t, 1, original.Version)
+
+		_, err = repository.GetRevision(ctx, user.UserId, 4)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		asOf, err := repository.GetAsOf(ctx, user.UserId, afterCreate)
+		require.NoError(t, err)
+		assert.Equal(t, "Ada", asOf.FirstName)
+
+		_, err = repository.GetAsOf(ctx, user.UserId, beforeCreate)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		_, err = repository.GetAsOf(ctx, user.UserId, time.Now().Add(time.Hour))
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		_, err = repository.GetHistory(ctx, uuid.New())
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+}
+
+func userIDs(users []domain.User) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.UserId)
+	}
+	return ids
+}
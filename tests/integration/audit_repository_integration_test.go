@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/internal/audit"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepository(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	auditRepository := repository.NewAuditRepository(connectionPool, []byte("audit-test-key"))
+	target := uuid.New()
+
+	t.Run("AppendChainsEntries", func(t *testing.T) {
+		first, err := auditRepository.Append(context.Background(), audit.Entry{
+			OccurredAt: time.Now(),
+			Actor:      "support",
+			SourceIP:   "10.0.0.1",
+			Method:     "POST",
+			Route:      "/users",
+			TargetUser: target,
+			Status:     201,
+			Outcome:    audit.OutcomeSuccess,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, audit.GenesisHash, first.PrevHash)
+
+		second, err := auditRepository.Append(context.Background(), audit.Entry{
+			OccurredAt: time.Now(),
+			SourceIP:   "10.0.0.2",
+			Method:     "DELETE",
+			Route:      "/users/{id}",
+			Status:     404,
+			Outcome:    audit.OutcomeFailure,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, first.Hash, second.PrevHash)
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		entries, err := auditRepository.List(context.Background(), audit.Filter{TargetUser: target, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "support", entries[0].Actor)
+
+		entries, err = auditRepository.List(context.Background(), audit.Filter{Outcome: audit.OutcomeFailure, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, uuid.Nil, entries[0].TargetUser)
+	})
+
+	t.Run("VerifyChain", func(t *testing.T) {
+		checked, err := audit.Verify(context.Background(), auditRepository, []byte("audit-test-key"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, checked)
+	})
+
+	t.Run("RejectsEdits", func(t *testing.T) {
+		_, err := connectionPool.Exec(context.Background(), "UPDATE audit_log SET actor = 'someone-else'")
+		assert.Error(t, err)
+	})
+}
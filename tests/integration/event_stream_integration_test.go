@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/stream"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventStreamListener(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	broker := stream.NewBroker(10)
+	listener := &stream.Listener{Pool: connectionPool, Broker: broker, RetryDelay: 100 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		listener.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	subscription, _, _ := broker.Subscribe("")
+	defer subscription.Close()
+
+	// Give the listener time to start listening.
+	time.Sleep(500 * time.Millisecond)
+
+	userRepository := repository.NewUserRepository(connectionPool)
+	user := domain.User{
+		FirstName:   "Live",
+		LastName:    "Stream",
+		Email:       "stream@gmail.com",
+		Phone:       "1234567890",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+		UserId:      uuid.New(),
+	}
+
+	t.Run("PublishesCommittedWrites", func(t *testing.T) {
+		_, err := userRepository.Create(context.Background(), &user)
+		assert.NoError(t, err)
+
+		_, err = userRepository.Delete(context.Background(), user.UserId, 0)
+		assert.NoError(t, err)
+
+		for _, eventType := range []string{"user.created", "user.deleted"} {
+			select {
+			case event := <-subscription.Events():
+				assert.Equal(t, eventType, event.Type)
+				assert.Equal(t, user.UserId, event.UserID)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no %s event received", eventType)
+			}
+		}
+	})
+
+	t.Run("FailedWritePublishesNothing", func(t *testing.T) {
+		_, err := userRepository.ChangeStatus(context.Background(), uuid.New(), domain.UserStatusActive, "unknown user", 0)
+		assert.Error(t, err)
+
+		select {
+		case event := <-subscription.Events():
+			t.Fatalf("unexpected %s event", event.Type)
+		case <-time.After(500 * time.Millisecond):
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"user-management/internal/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecks(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	checker := &health.Checker{}
+	checker.Add("database", health.Database(connectionPool))
+	checker.Add("schema", health.Schema(connectionPool))
+	checker.Add("pool", health.Pool(connectionPool, 0.9))
+
+	report := checker.Run(context.Background())
+	assert.Equal(t, health.StatusUp, report.Status)
+	assert.Equal(t, false, report.Checks["schema"].Details["dirty"])
+	assert.Contains(t, report.Checks["pool"].Details, "saturation")
+
+	// A saturation threshold of zero is always reached.
+	checker = &health.Checker{}
+	checker.Add("pool", health.Pool(connectionPool, 0))
+	assert.Equal(t, health.StatusDegraded, checker.Run(context.Background()).Status)
+}
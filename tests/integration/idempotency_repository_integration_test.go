@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	store := repository.NewIdempotencyRepository(connectionPool)
+	ctx := context.Background()
+
+	t.Run("ClaimsOnceAndReplaysCompletedResponse", func(t *testing.T) {
+		claim, _, err := store.Claim(ctx, "complete", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, claim)
+
+		held, stored, err := store.Claim(ctx, "complete", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+		assert.Equal(t, "hash-1", stored.RequestHash)
+		assert.Zero(t, stored.StatusCode)
+
+		err = store.Complete(ctx, "complete", claim, domain.IdempotentResponse{
+			RequestHash: "hash-1",
+			StatusCode:  http.StatusCreated,
+			Header:      map[string][]string{"Location": {"/users/1"}},
+			Body:        []byte(`{"id":1}`),
+		})
+		assert.NoError(t, err)
+
+		held, stored, err = store.Claim(ctx, "complete", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+		assert.Equal(t, http.StatusCreated, stored.StatusCode)
+		assert.Equal(t, []string{"/users/1"}, stored.Header["Location"])
+		assert.Equal(t, `{"id":1}`, string(stored.Body))
+	})
+
+	t.Run("ReleaseFreesKey", func(t *testing.T) {
+		claim, _, err := store.Claim(ctx, "release", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+
+		assert.NoError(t, store.Release(ctx, "release", claim))
+
+		claim, _, err = store.Claim(ctx, "release", "hash-2", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, claim)
+	})
+
+	t.Run("ReleaseKeepsCompletedResponse", func(t *testing.T) {
+		claim, _, err := store.Claim(ctx, "kept", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Complete(ctx, "kept", claim, domain.IdempotentResponse{RequestHash: "hash-1", StatusCode: http.StatusCreated}))
+
+		assert.NoError(t, store.Release(ctx, "kept", claim))
+
+		held, stored, err := store.Claim(ctx, "kept", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+		assert.Equal(t, http.StatusCreated, stored.StatusCode)
+	})
+
+	t.Run("TakesOverClaimWithExpiredLease", func(t *testing.T) {
+		abandoned, _, err := store.Claim(ctx, "abandoned", "hash-1", time.Hour, 0)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, abandoned)
+
+		claim, _, err := store.Claim(ctx, "abandoned", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, claim)
+
+		held, _, err := store.Claim(ctx, "abandoned", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+	})
+
+	t.Run("SupersededClaimCannotReleaseOrComplete", func(t *testing.T) {
+		stale, _, err := store.Claim(ctx, "superseded", "hash-1", time.Hour, 0)
+		assert.NoError(t, err)
+
+		claim, _, err := store.Claim(ctx, "superseded", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+
+		// The late release leaves the new claim in place.
+		assert.NoError(t, store.Release(ctx, "superseded", stale))
+		held, _, err := store.Claim(ctx, "superseded", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+
+		err = store.Complete(ctx, "superseded", stale, domain.IdempotentResponse{RequestHash: "hash-1", StatusCode: http.StatusConflict})
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		assert.NoError(t, store.Complete(ctx, "superseded", claim, domain.IdempotentResponse{RequestHash: "hash-1", StatusCode: http.StatusCreated}))
+
+		_, stored, err := store.Claim(ctx, "superseded", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, stored.StatusCode)
+	})
+
+	t.Run("KeepsCompletedResponseAfterLease", func(t *testing.T) {
+		claim, _, err := store.Claim(ctx, "finished", "hash-1", time.Hour, 0)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Complete(ctx, "finished", claim, domain.IdempotentResponse{RequestHash: "hash-1", StatusCode: http.StatusCreated}))
+
+		held, stored, err := store.Claim(ctx, "finished", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+		assert.Equal(t, http.StatusCreated, stored.StatusCode)
+	})
+
+	t.Run("DeleteExpiredRemovesOnlyExpiredKeys", func(t *testing.T) {
+		_, _, err := store.Claim(ctx, "expired", "hash-1", 0, time.Minute)
+		assert.NoError(t, err)
+		_, _, err = store.Claim(ctx, "live", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+
+		deleted, err := store.DeleteExpired(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		held, _, err := store.Claim(ctx, "live", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.Nil, held)
+
+		claim, _, err := store.Claim(ctx, "expired", "hash-1", time.Hour, time.Minute)
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, claim)
+	})
+}
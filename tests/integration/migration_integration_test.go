@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"user-management/internal/migration"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator(t *testing.T) {
+	container, _, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	host, err := container.Host(context.Background())
+	require.NoError(t, err)
+	port, err := container.MappedPort(context.Background(), "5432")
+	require.NoError(t, err)
+
+	migrator, err := migration.New(fmt.Sprintf("postgres://postgres:25621@%v:%v/test_db?sslmode=disable", host, port.Port()))
+	require.NoError(t, err)
+	defer func() { _ = migrator.Close() }()
+
+	available, err := migration.Available()
+	require.NoError(t, err)
+	latest := available[len(available)-1].Version
+
+	t.Run("StartsAtLatest", func(t *testing.T) {
+		status, err := migrator.Status()
+		assert.NoError(t, err)
+		assert.Equal(t, migration.Status{Version: latest, Latest: latest}, status)
+		assert.NoError(t, migrator.Up())
+	})
+
+	t.Run("DownAndGoto", func(t *testing.T) {
+		assert.NoError(t, migrator.Down(2))
+
+		status, err := migrator.Status()
+		assert.NoError(t, err)
+		assert.Equal(t, latest-2, status.Version)
+
+		assert.NoError(t, migrator.Goto(latest))
+
+		status, err = migrator.Status()
+		assert.NoError(t, err)
+		assert.Equal(t, latest, status.Version)
+
+		assert.Error(t, migrator.Down(0))
+	})
+
+	t.Run("ForceRecordsVersion", func(t *testing.T) {
+		assert.NoError(t, migrator.Force(int(latest)+1))
+
+		status, err := migrator.Status()
+		assert.NoError(t, err)
+		assert.Error(t, status.Check())
+
+		assert.NoError(t, migrator.Force(int(latest)))
+	})
+}
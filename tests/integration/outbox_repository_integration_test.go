@@ -0,0 +1,174 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/outbox"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	userRepository := repository.NewUserRepository(connectionPool)
+	publisher := &outbox.MemoryPublisher{}
+	dispatcher := &outbox.Dispatcher{
+		Store:       repository.NewOutboxRepository(connectionPool),
+		Publisher:   publisher,
+		BatchSize:   10,
+		Lease:       time.Minute,
+		MaxAttempts: 2,
+	}
+
+	user := domain.User{
+		FirstName:   "Event",
+		LastName:    "Source",
+		Email:       "events@gmail.com",
+		Phone:       "1234567890",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+		UserId:      uuid.New(),
+	}
+
+	t.Run("WritesEventsWithChanges", func(t *testing.T) {
+		_, err := userRepository.Create(context.Background(), &user)
+		assert.NoError(t, err)
+
+		_, err = userRepository.ChangeStatus(context.Background(), user.UserId, domain.UserStatusSuspended, "fraud review", 0)
+		assert.NoError(t, err)
+
+		_, err = userRepository.Delete(context.Background(), user.UserId, 0)
+		assert.NoError(t, err)
+
+		delivered, err := dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, delivered)
+
+		messages := publisher.Messages()
+		assert.Equal(t, "user.created", messages[0].Type)
+		assert.Equal(t, "user.status_changed", messages[1].Type)
+		assert.Equal(t, "user.deleted", messages[2].Type)
+		assert.Equal(t, user.UserId, messages[1].Key)
+
+		var event domain.UserEvent
+		assert.NoError(t, json.Unmarshal(messages[1].Payload, &event))
+		assert.Equal(t, "fraud review", event.Reason)
+		assert.Equal(t, 2, event.Version)
+
+		delivered, err = dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("FailedWriteLeavesNoEvent", func(t *testing.T) {
+		_, err := userRepository.ChangeStatus(context.Background(), uuid.New(), domain.UserStatusActive, "unknown user", 0)
+		assert.Error(t, err)
+
+		delivered, err := dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("RetriesFailedPublish", func(t *testing.T) {
+		_, err := userRepository.Restore(context.Background(), user.UserId)
+		assert.NoError(t, err)
+
+		publisher.Err = errors.New("broker unavailable")
+		delivered, err := dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+
+		publisher.Err = nil
+		delivered, err = dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+
+		messages := publisher.Messages()
+		assert.Equal(t, "user.restored", messages[len(messages)-1].Type)
+		assert.Equal(t, 1, messages[len(messages)-1].Attempts)
+	})
+
+	t.Run("ParksMessageOutOfAttempts", func(t *testing.T) {
+		_, err := userRepository.ChangeStatus(context.Background(), user.UserId, domain.UserStatusActive, "parked", 0)
+		assert.NoError(t, err)
+
+		publisher.Err = errors.New("broker unavailable")
+		for range 2 {
+			delivered, err := dispatcher.DispatchOnce(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, delivered)
+		}
+
+		var status string
+		var attempts int
+		err = connectionPool.QueryRow(context.Background(),
+			"SELECT status, attempts FROM outbox ORDER BY id DESC LIMIT 1").Scan(&status, &attempts)
+		assert.NoError(t, err)
+		assert.Equal(t, "parked", status)
+		assert.Equal(t, 2, attempts)
+
+		// Parked messages are not claimed again, so later events go out.
+		publisher.Err = nil
+		_, err = userRepository.ChangeStatus(context.Background(), user.UserId, domain.UserStatusSuspended, "unparked", 0)
+		assert.NoError(t, err)
+
+		delivered, err := dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+	})
+
+	t.Run("ClaimHoldsMessagesForLease", func(t *testing.T) {
+		store := repository.NewOutboxRepository(connectionPool)
+
+		_, err := userRepository.ChangeStatus(context.Background(), user.UserId, domain.UserStatusActive, "leased", 0)
+		assert.NoError(t, err)
+
+		claimed, err := store.Claim(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Len(t, claimed, 1)
+
+		again, err := store.Claim(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Empty(t, again)
+
+		assert.NoError(t, store.Release(context.Background(), []int64{claimed[0].ID}))
+
+		again, err = store.Claim(context.Background(), 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Len(t, again, 1)
+		assert.NoError(t, store.MarkDelivered(context.Background(), again[0].ID))
+	})
+	t.Run("DeletesDeliveredMessagesPastRetention", func(t *testing.T) {
+		store := repository.NewOutboxRepository(connectionPool)
+
+		deleted, err := store.DeleteDelivered(context.Background(), time.Hour)
+		assert.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		// Everything delivered so far is older than a retention of zero.
+		deleted, err = store.DeleteDelivered(context.Background(), 0)
+		assert.NoError(t, err)
+		assert.Positive(t, deleted)
+
+		var statuses []string
+		rows, err := connectionPool.Query(context.Background(), "SELECT status FROM outbox")
+		assert.NoError(t, err)
+		for rows.Next() {
+			var status string
+			assert.NoError(t, rows.Scan(&status))
+			statuses = append(statuses, status)
+		}
+		assert.NoError(t, rows.Err())
+		assert.Equal(t, []string{"parked"}, statuses)
+	})
+}
@@ -3,14 +3,11 @@ package integration
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
-	"user-management/migrations"
+	"user-management/internal/migration"
+	"user-management/internal/tracing"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/testcontainers/testcontainers-go"
@@ -59,35 +56,31 @@ func SetupTestDatabase() (testcontainers.Container, *pgxpool.Pool, error) {
 	connectionString := fmt.Sprintf("postgres://postgres:25621@%v:%v/test_db", host, port.Port())
 	err = MigrateDb(connectionString)
 
-	connectionPool, err := pgxpool.New(ctx, connectionString)
+	config, err := pgxpool.ParseConfig(connectionString)
 
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return dbContainer, connectionPool, err
-
-}
-
-func MigrateDb(connectionString string) (err error) {
-	source, err := iofs.New(migrations.FS, ".")
-	if err != nil {
-		return err
-	}
+	config.ConnConfig.Tracer = tracing.QueryTracer{}
 
-	m, err := migrate.NewWithSourceInstance("iofs", source, strings.Replace(connectionString, "postgres://", "pgx5://", 1))
+	connectionPool, err := pgxpool.NewWithConfig(ctx, config)
 
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	err = m.Up()
+	return dbContainer, connectionPool, err
 
-	defer m.Close()
+}
 
+func MigrateDb(connectionString string) (err error) {
+	migrator, err := migration.New(connectionString)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	defer func() { _ = migrator.Close() }()
+
+	return migrator.Up()
 }
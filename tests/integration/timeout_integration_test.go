@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeouts(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	config := connectionPool.Config()
+	config.ConnConfig.RuntimeParams["statement_timeout"] = "300"
+	config.ConnConfig.BuildContextWatcherHandler = func(conn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: conn, DeadlineDelay: time.Second}
+	}
+
+	limitedPool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	defer limitedPool.Close()
+
+	user := domain.User{
+		UserId:      uuid.New(),
+		FirstName:   "Slow",
+		LastName:    "Tester",
+		Email:       "slow@example.com",
+		Phone:       "1234567890",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+	}
+	_, err = repository.NewUserRepository(connectionPool).Create(ctx, &user)
+	require.NoError(t, err)
+
+	// Hold the user's row lock so that writes to it wait.
+	lock, err := connectionPool.Begin(ctx)
+	require.NoError(t, err)
+	defer lock.Rollback(ctx)
+
+	_, err = lock.Exec(ctx, "SELECT 1 FROM users WHERE user_id = $1 FOR UPDATE", user.UserId)
+	require.NoError(t, err)
+
+	t.Run("RequestDeadline", func(t *testing.T) {
+		userRepository := repository.NewUserRepository(connectionPool)
+
+		requestCtx, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		_, err := userRepository.Update(requestCtx, user.UserId, &domain.User{FirstName: "Waiting"})
+
+		assert.Equal(t, domain.ErrorKindTimeout, domain.ErrorKindOf(err))
+		assert.Less(t, time.Since(started), 2*time.Second)
+	})
+
+	t.Run("ClientCancellation", func(t *testing.T) {
+		userRepository := repository.NewInstrumentedUserRepository(repository.NewUserRepository(limitedPool), nil)
+
+		requestCtx, cancel := context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		_, err := userRepository.Update(requestCtx, user.UserId, &domain.User{FirstName: "Waiting"})
+
+		// A client going away is neither a timeout nor an outage.
+		assert.Equal(t, domain.ErrorKindCanceled, domain.ErrorKindOf(err))
+	})
+
+	t.Run("StatementTimeout", func(t *testing.T) {
+		userRepository := repository.NewUserRepository(limitedPool)
+
+		_, err := userRepository.Update(ctx, user.UserId, &domain.User{FirstName: "Waiting"})
+		assert.Equal(t, domain.ErrorKindTimeout, domain.ErrorKindOf(err))
+
+		// The cancelled statement leaves its connection usable.
+		_, err = userRepository.GetById(ctx, user.UserId)
+		assert.NoError(t, err)
+	})
+}
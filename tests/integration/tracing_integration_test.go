@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/api/route/users"
+	"user-management/bootstrap"
+	"user-management/internal/validator"
+	"user-management/tests/spans"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingFromRequestToStatement(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	exporter := spans.Record(t)
+	validator.Init()
+
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Trace)
+		users.UserRouter(&bootstrap.Env{CursorSecret: "secret"}, slog.Default(), connectionPool, nil, r)
+	})
+
+	body := `{"firstName":"Traced","lastName":"Tester","email":"traced@example.com","phone":"+14155550100","dateOfBirth":"1990-04-21"}`
+	request := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, request)
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	server := spans.Named(t, exporter, "POST /users")
+	create := spans.Named(t, exporter, "UserRepository.Create")
+	assert.Equal(t, server.SpanContext.SpanID(), create.Parent.SpanID())
+
+	var statements []string
+	for _, span := range exporter.GetSpans() {
+		if span.Parent.SpanID() == create.SpanContext.SpanID() {
+			statements = append(statements, span.Name)
+		}
+	}
+	assert.Subset(t, statements, []string{"CreateUser", "CreateOutboxEvent", "NotifyUserEvent"})
+
+	insert := spans.Named(t, exporter, "CreateUser")
+	assert.Equal(t, "postgresql", spans.Attribute(insert, "db.system.name"))
+	assert.NotContains(t, statementArguments(insert), "traced@example.com")
+}
+
+// statementArguments joins every attribute value recorded on span.
+func statementArguments(span tracetest.SpanStub) string {
+	var values []string
+	for _, attribute := range span.Attributes {
+		values = append(values, attribute.Value.Emit())
+	}
+	return strings.Join(values, " ")
+}
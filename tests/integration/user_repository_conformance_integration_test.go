@@ -0,0 +1,26 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"user-management/domain"
+	"user-management/repository"
+	"user-management/tests/conformance"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryConformance(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	conformance.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+		// History and status changes go with the users they belong to.
+		_, err := connectionPool.Exec(context.Background(), "TRUNCATE users CASCADE")
+		require.NoError(t, err)
+
+		return repository.NewUserRepository(connectionPool)
+	})
+}
@@ -3,6 +3,7 @@ package integration
 import (
 	"context"
 	"testing"
+	"time"
 	"user-management/domain"
 	"user-management/repository"
 
@@ -19,41 +20,102 @@ func TestUserRespository(t *testing.T) {
 	userRepository := repository.NewUserRepository(connectionPool)
 
 	newUser := domain.User{
-		FirstName: "Test",
-		LastName:  "User",
-		Email:     "abc@gmail.com",
-		Phone:     "1234567890",
-		Age:       25,
-		Status:    domain.UserStatusActive,
-		UserId:    uuid.New(),
+		FirstName:   "Test",
+		LastName:    "User",
+		Email:       "abc@gmail.com",
+		Phone:       "1234567890",
+		DateOfBirth: time.Date(2000, time.March, 15, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+		UserId:      uuid.New(),
 	}
 
 	t.Run("CreateNewUser", func(t *testing.T) {
-		created, err := userRepository.Create(context.Background(), &newUser)
+		created, err := userRepository.Create(domain.WithActor(context.Background(), "provisioner"), &newUser)
 		assert.NoError(t, err)
 		assert.NotNil(t, created)
+		assert.True(t, created.CreatedAt.Valid)
+		assert.Equal(t, "provisioner", created.CreatedBy.String)
+		assert.Equal(t, "provisioner", created.UpdatedBy.String)
+	})
+
+	t.Run("CreateUserWithDuplicateEmail", func(t *testing.T) {
+		duplicate := newUser
+		duplicate.UserId = uuid.New()
+
+		_, err := userRepository.Create(context.Background(), &duplicate)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
 	})
 
 	t.Run("GetAllUsers", func(t *testing.T) {
-		users, err := userRepository.GetAll(context.Background())
+		users, total, err := userRepository.GetAll(context.Background(), domain.UserQuery{
+			SortBy: domain.UserSortByFirstName,
+			Limit:  10,
+		})
 		assert.NoError(t, err)
 		assert.NotEmpty(t, users)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("GetAllUsersWithFilters", func(t *testing.T) {
+		minAge := 30
+
+		users, total, err := userRepository.GetAll(context.Background(), domain.UserQuery{
+			Filter: domain.UserFilter{NamePrefix: "te", MinAge: &minAge},
+			SortBy: domain.UserSortByAge,
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+		assert.Equal(t, int64(0), total)
+
+		users, total, err = userRepository.GetAll(context.Background(), domain.UserQuery{
+			Filter: domain.UserFilter{Email: "ABC@gmail.com"},
+			SortBy: domain.UserSortByEmail,
+			Limit:  10,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("GetAllUsersAfterCursor", func(t *testing.T) {
+		query := domain.UserQuery{SortBy: domain.UserSortByFirstName, Limit: 10}
+
+		users, err := userRepository.GetAllAfter(context.Background(), query, domain.UserCursor{
+			SortBy:     domain.UserSortByFirstName,
+			LastValue:  "A",
+			LastUserId: uuid.Nil,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, users, 1)
+
+		users, err = userRepository.GetAllAfter(context.Background(), query, domain.NewUserCursor(newUser, query.SortBy, false))
+		assert.NoError(t, err)
+		assert.Empty(t, users)
 	})
 
 	t.Run("GetUserById", func(t *testing.T) {
 		user, err := userRepository.GetById(context.Background(), newUser.UserId)
+		expected := newUser
+		expected.Version = 1
+		expected.CreatedBy = "provisioner"
+		expected.UpdatedBy = "provisioner"
+		expected.Age = domain.AgeOn(newUser.DateOfBirth, time.Now())
+		expected.CreatedAt = user.CreatedAt
+		expected.UpdatedAt = user.UpdatedAt
 		assert.NoError(t, err)
-		assert.Equal(t, newUser, user)
+		assert.Equal(t, expected, user)
+		assert.False(t, user.CreatedAt.IsZero())
 	})
 
 	t.Run("GetUserByIdForNonExistingUserId", func(t *testing.T) {
 		user, err := userRepository.GetById(context.Background(), uuid.New())
-		assert.Error(t, err)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
 		assert.Empty(t, user)
 	})
 
 	t.Run("DeleteUser", func(t *testing.T) {
-		deletedID, err := userRepository.Delete(context.Background(), newUser.UserId)
+		deletedID, err := userRepository.Delete(context.Background(), newUser.UserId, 0)
 		assert.NoError(t, err)
 		assert.Equal(t, newUser.UserId, deletedID)
 
@@ -63,20 +125,135 @@ func TestUserRespository(t *testing.T) {
 	})
 
 	t.Run("UpdateUser", func(t *testing.T) {
-		userRepository.Create(context.Background(), &newUser)
+		// The deleted row is still there, but its email is free to reuse.
+		newUser.UserId = uuid.New()
+		_, err := userRepository.Create(context.Background(), &newUser)
+		assert.NoError(t, err)
 
 		updatedUserRequest := domain.User{
-			FirstName: "UpdatedFirstName",
-			LastName:  "UpdatedLastName",
-			Email:     "updatedEmail@email.com",
-			Phone:     "updatedPhone",
-			Age:       26,
-			Status:    domain.UserStatusInactive,
-			UserId:    newUser.UserId,
+			FirstName:   "UpdatedFirstName",
+			LastName:    "UpdatedLastName",
+			Email:       "updatedEmail@email.com",
+			Phone:       "updatedPhone",
+			DateOfBirth: time.Date(1999, time.March, 15, 0, 0, 0, 0, time.UTC),
+			Status:      domain.UserStatusDeactivated,
+			UserId:      newUser.UserId,
 		}
-		updatedUserRow, _ := userRepository.Update(context.Background(), newUser.UserId, &updatedUserRequest)
+		updatedUserRow, _ := userRepository.Update(domain.WithActor(context.Background(), "support"), newUser.UserId, &updatedUserRequest)
 		assert.NotEmpty(t, updatedUserRow)
 		assert.Equal(t, updatedUserRequest.Email, updatedUserRow.Email)
 		assert.Equal(t, updatedUserRequest.UserId, repository.ToUUIDFromPgUUID(updatedUserRow.UserID))
+		assert.Equal(t, int32(2), updatedUserRow.Version)
+		assert.Equal(t, "support", updatedUserRow.UpdatedBy.String)
+		assert.False(t, updatedUserRow.UpdatedAt.Time.Before(updatedUserRow.CreatedAt.Time))
+	})
+
+	t.Run("UpdateUserWithStaleVersion", func(t *testing.T) {
+		stale := domain.User{FirstName: "Stale", Version: 1}
+
+		_, err := userRepository.Update(context.Background(), newUser.UserId, &stale)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		_, err = userRepository.Delete(context.Background(), newUser.UserId, 1)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		_, err = userRepository.Update(context.Background(), uuid.New(), &stale)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("ReplaceUserClearsFields", func(t *testing.T) {
+		current, err := userRepository.GetById(context.Background(), newUser.UserId)
+		assert.NoError(t, err)
+
+		current.Status = domain.UserStatusActive
+
+		replaced, err := userRepository.Replace(context.Background(), &current)
+		assert.NoError(t, err)
+		assert.Equal(t, string(domain.UserStatusActive), replaced.Status)
+		assert.Equal(t, int32(current.Version+1), replaced.Version)
+
+		_, err = userRepository.Replace(context.Background(), &current)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+	})
+
+	t.Run("ChangeStatusFollowsLifecycle", func(t *testing.T) {
+		suspended, err := userRepository.ChangeStatus(context.Background(), newUser.UserId, domain.UserStatusSuspended, "fraud review", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, string(domain.UserStatusSuspended), suspended.Status)
+
+		_, err = userRepository.ChangeStatus(context.Background(), newUser.UserId, domain.UserStatusLocked, "too many attempts", 0)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		_, err = userRepository.ChangeStatus(context.Background(), newUser.UserId, domain.UserStatusActive, "review passed", 1)
+		assert.Equal(t, domain.ErrorKindPreconditionFailed, domain.ErrorKindOf(err))
+
+		_, err = userRepository.ChangeStatus(context.Background(), uuid.New(), domain.UserStatusActive, "unknown user", 0)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("HistoryAndAsOf", func(t *testing.T) {
+		revisions, err := userRepository.GetHistory(context.Background(), newUser.UserId)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 4)
+		assert.Equal(t, domain.UserCreated, revisions[0].Operation)
+		assert.Equal(t, "support", revisions[1].ChangedBy)
+		assert.Equal(t, domain.FieldChange{From: "deactivated", To: "active"}, revisions[2].Changes["status"])
+
+		original, err := userRepository.GetRevision(context.Background(), newUser.UserId, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, newUser.FirstName, original.FirstName)
+
+		updated, err := userRepository.GetRevision(context.Background(), newUser.UserId, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "UpdatedFirstName", updated.FirstName)
+		assert.Equal(t, domain.UserStatusDeactivated, updated.Status)
+
+		_, err = userRepository.GetRevision(context.Background(), newUser.UserId, 99)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		latest, err := userRepository.GetAsOf(context.Background(), newUser.UserId, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.UserStatusSuspended, latest.Status)
+		assert.Equal(t, 4, latest.Version)
+
+		_, err = userRepository.GetAsOf(context.Background(), newUser.UserId, revisions[0].ChangedAt.Add(-time.Second))
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+
+	t.Run("RestoreAndPurgeDeletedUser", func(t *testing.T) {
+		deletedUser := domain.User{
+			FirstName:   "Deleted",
+			LastName:    "User",
+			Email:       "deleted@gmail.com",
+			Phone:       "1234567890",
+			DateOfBirth: time.Date(1995, time.March, 15, 0, 0, 0, 0, time.UTC),
+			Status:      domain.UserStatusActive,
+			UserId:      uuid.New(),
+		}
+		_, err := userRepository.Create(context.Background(), &deletedUser)
+		assert.NoError(t, err)
+
+		_, err = userRepository.Restore(context.Background(), deletedUser.UserId)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+
+		_, err = userRepository.Delete(context.Background(), deletedUser.UserId, 0)
+		assert.NoError(t, err)
+
+		restored, err := userRepository.Restore(context.Background(), deletedUser.UserId)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), restored.Version)
+
+		_, err = userRepository.Delete(context.Background(), deletedUser.UserId, 0)
+		assert.NoError(t, err)
+
+		purged, err := userRepository.PurgeDeleted(context.Background(), time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, purged, int64(1))
+
+		_, err = userRepository.Restore(context.Background(), deletedUser.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+
+		err = userRepository.Purge(context.Background(), deletedUser.UserId)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
 	})
 }
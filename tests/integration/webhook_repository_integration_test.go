@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/outbox"
+	"user-management/internal/webhook"
+	"user-management/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	_, connectionPool, err := SetupTestDatabase()
+	if err != nil {
+		return
+	}
+
+	var mu sync.Mutex
+	var received []domain.UserEvent
+	failing := false
+	setFailing := func(fail bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		failing = fail
+	}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var event domain.UserEvent
+		_ = json.Unmarshal(body, &event)
+		received = append(received, event)
+	}))
+	defer receiver.Close()
+
+	webhooks := repository.NewWebhookRepository(connectionPool)
+	userRepository := repository.NewUserRepository(connectionPool)
+	dispatcher := &outbox.Dispatcher{
+		Store:     repository.NewOutboxRepository(connectionPool),
+		Publisher: webhook.Fanout{Store: webhooks},
+		BatchSize: 10,
+	}
+	deliverer := &webhook.Deliverer{
+		Store:       webhooks,
+		BatchSize:   10,
+		Timeout:     time.Second,
+		MaxAttempts: 2,
+		RetryDelay:  0,
+	}
+
+	subscription, err := webhooks.CreateSubscription(context.Background(), webhook.Subscription{
+		ID:         uuid.New(),
+		URL:        receiver.URL,
+		EventTypes: []string{"user.created", "user.deleted"},
+		Secret:     "0123456789abcdef",
+	})
+	assert.NoError(t, err)
+
+	user := domain.User{
+		FirstName:   "Hook",
+		LastName:    "Receiver",
+		Email:       "hooks@gmail.com",
+		Phone:       "1234567890",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+		UserId:      uuid.New(),
+	}
+
+	t.Run("DeliversSubscribedEvents", func(t *testing.T) {
+		_, err := userRepository.Create(context.Background(), &user)
+		assert.NoError(t, err)
+		_, err = userRepository.ChangeStatus(context.Background(), user.UserId, domain.UserStatusSuspended, "review", 0)
+		assert.NoError(t, err)
+
+		_, err = dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+
+		attempted, err := deliverer.DeliverOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempted)
+
+		assert.Len(t, received, 1)
+		assert.Equal(t, domain.UserEventCreated, received[0].Type)
+
+		deliveries, err := webhooks.ListDeliveries(context.Background(), subscription.ID, webhook.DeliveryDelivered, 0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, deliveries, 1)
+
+		attempts, err := webhooks.ListAttempts(context.Background(), subscription.ID, deliveries[0].ID)
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 1)
+		assert.Equal(t, http.StatusOK, attempts[0].StatusCode)
+	})
+
+	t.Run("DeadLettersAndReplays", func(t *testing.T) {
+		setFailing(true)
+
+		_, err := userRepository.Delete(context.Background(), user.UserId, 0)
+		assert.NoError(t, err)
+		_, err = dispatcher.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = deliverer.DeliverOnce(context.Background())
+			assert.NoError(t, err)
+		}
+
+		dead, err := webhooks.ListDeliveries(context.Background(), subscription.ID, webhook.DeliveryDead, 0, 10)
+		assert.NoError(t, err)
+		assert.Len(t, dead, 1)
+		assert.Equal(t, 2, dead[0].Attempts)
+
+		setFailing(false)
+
+		replayed, err := webhooks.Replay(context.Background(), subscription.ID, dead[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, webhook.DeliveryPending, replayed.Status)
+
+		_, err = deliverer.DeliverOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.UserEventDeleted, received[len(received)-1].Type)
+
+		attempts, err := webhooks.ListAttempts(context.Background(), subscription.ID, dead[0].ID)
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 3)
+	})
+
+	t.Run("DeleteSubscription", func(t *testing.T) {
+		assert.NoError(t, webhooks.DeleteSubscription(context.Background(), subscription.ID))
+
+		_, err := webhooks.GetSubscription(context.Background(), subscription.ID)
+		assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	})
+}
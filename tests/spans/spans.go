@@ -0,0 +1,54 @@
+// Package spans records the spans ended during a test so their structure can
+// be checked.
+package spans
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Record installs a global tracer provider that keeps every span in memory
+// until the test ends. Instrumentation created before it is called keeps
+// reporting to the provider it was created with.
+func Record(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(t.Context())
+	})
+
+	return exporter
+}
+
+// Named returns the one ended span called name.
+func Named(t *testing.T, exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	var found []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+
+	require.Len(t, found, 1, "spans named %q", name)
+
+	return found[0]
+}
+
+// Attribute returns the value of the attribute key on span, or nil.
+func Attribute(span tracetest.SpanStub, key string) any {
+	for _, attribute := range span.Attributes {
+		if string(attribute.Key) == key {
+			return attribute.Value.AsInterface()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/api/controller/auditlog"
+	"user-management/internal/audit"
+	"user-management/internal/validator"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// pagedStore holds entries 1 to 5 and remembers the last filter it saw.
+type pagedStore struct {
+	filter audit.Filter
+}
+
+func (p *pagedStore) Append(c context.Context, entry audit.Entry) (audit.Entry, error) {
+	return entry, nil
+}
+
+func (p *pagedStore) List(c context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	p.filter = filter
+
+	var entries []audit.Entry
+	for id := filter.AfterID + 1; id <= 5 && len(entries) < filter.Limit; id++ {
+		entries = append(entries, audit.Entry{ID: id, Outcome: audit.OutcomeSuccess})
+	}
+	return entries, nil
+}
+
+func TestListEntries(t *testing.T) {
+	validator.Init()
+	store := &pagedStore{}
+	controller := auditlog.AuditController{Store: store}
+	target := uuid.New()
+
+	request := httptest.NewRequest(http.MethodGet, "/audit?limit=2&after=1&actor=support&outcome=success&userId="+target.String(), nil)
+	rr := httptest.NewRecorder()
+	controller.ListEntries(rr, request)
+
+	var response auditlog.EntryListResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, int64(2), response.Data[0].ID)
+	assert.Equal(t, int64(3), *response.NextAfter)
+	assert.Equal(t, "support", store.filter.Actor)
+	assert.Equal(t, target, store.filter.TargetUser)
+	assert.Equal(t, audit.OutcomeSuccess, store.filter.Outcome)
+
+	request = httptest.NewRequest(http.MethodGet, "/audit?after=3", nil)
+	rr = httptest.NewRecorder()
+	controller.ListEntries(rr, request)
+
+	response = auditlog.EntryListResponse{}
+	_ = json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Len(t, response.Data, 2)
+	assert.Nil(t, response.NextAfter)
+}
+
+func TestListEntriesRejectsInvalidFilters(t *testing.T) {
+	validator.Init()
+	controller := auditlog.AuditController{Store: &pagedStore{}}
+
+	for _, query := range []string{"?userId=nope", "?outcome=maybe", "?limit=0", "?limit=501", "?from=yesterday", "?after=-1"} {
+		rr := httptest.NewRecorder()
+		controller.ListEntries(rr, httptest.NewRequest(http.MethodGet, "/audit"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/api/controller/healthcheck"
+	"user-management/internal/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbes(t *testing.T) {
+	databaseStatus := health.StatusUp
+
+	checker := &health.Checker{}
+	checker.Add("database", func(ctx context.Context) health.Result {
+		return health.Result{Status: databaseStatus, Details: map[string]any{"host": "db"}}
+	})
+
+	hc := &healthcheck.HealthController{Checker: checker}
+
+	serve := func(handler http.HandlerFunc) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rr
+	}
+
+	rr := serve(hc.Ready)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"up"}`, rr.Body.String())
+
+	rr = serve(hc.Health)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var detailed healthcheck.HealthResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &detailed))
+	assert.Equal(t, health.StatusUp, detailed.Checks["database"].Status)
+	assert.Equal(t, "db", detailed.Checks["database"].Details["host"])
+
+	databaseStatus = health.StatusDown
+
+	rr = serve(hc.Ready)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.JSONEq(t, `{"status":"down"}`, rr.Body.String())
+
+	// Liveness ignores dependencies.
+	rr = serve(hc.Live)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	checker := &health.Checker{}
+	hc := &healthcheck.HealthController{Checker: checker}
+
+	checker.Drain()
+
+	rr := httptest.NewRecorder()
+	hc.Health(rr, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	var detailed healthcheck.HealthResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &detailed))
+	assert.True(t, detailed.Draining)
+
+	rr = httptest.NewRecorder()
+	hc.Live(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -0,0 +1,95 @@
+package subscription
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/api/controller/subscription"
+	"user-management/domain"
+	"user-management/internal/validator"
+	"user-management/internal/webhook"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type subscriptionStore struct {
+	webhook.Store
+	created webhook.Subscription
+}
+
+func (s *subscriptionStore) CreateSubscription(ctx context.Context, subscription webhook.Subscription) (webhook.Subscription, error) {
+	s.created = subscription
+	return subscription, nil
+}
+
+func (s *subscriptionStore) Replay(ctx context.Context, subscriptionID uuid.UUID, deliveryID int64) (webhook.Delivery, error) {
+	if deliveryID != 7 {
+		return webhook.Delivery{}, domain.NewNotFoundError("webhook delivery not found", nil)
+	}
+	return webhook.Delivery{ID: deliveryID, SubscriptionID: subscriptionID, Status: webhook.DeliveryPending}, nil
+}
+
+func TestCreateSubscription(t *testing.T) {
+	validator.Init()
+	store := &subscriptionStore{}
+	controller := subscription.SubscriptionController{Store: store}
+
+	body := `{"url":"https://example.com/hooks","eventTypes":["user.created","user.deleted"],"secret":"0123456789abcdef"}`
+	rr := httptest.NewRecorder()
+	controller.CreateSubscription(rr, httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Equal(t, "/webhooks/"+store.created.ID.String(), rr.Header().Get("Location"))
+	assert.Equal(t, "0123456789abcdef", store.created.Secret)
+	assert.NotContains(t, rr.Body.String(), "0123456789abcdef")
+}
+
+func TestCreateSubscriptionValidation(t *testing.T) {
+	validator.Init()
+	controller := subscription.SubscriptionController{Store: &subscriptionStore{}}
+
+	for _, body := range []string{
+		`{"url":"ftp://example.com","eventTypes":["user.created"],"secret":"0123456789abcdef"}`,
+		`{"url":"https://example.com","eventTypes":[],"secret":"0123456789abcdef"}`,
+		`{"url":"https://example.com","eventTypes":["user.renamed"],"secret":"0123456789abcdef"}`,
+		`{"url":"https://example.com","eventTypes":["user.created"],"secret":"short"}`,
+	} {
+		rr := httptest.NewRecorder()
+		controller.CreateSubscription(rr, httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestReplayDelivery(t *testing.T) {
+	controller := subscription.SubscriptionController{Store: &subscriptionStore{}}
+
+	r := chi.NewRouter()
+	r.Post("/webhooks/{id}/deliveries/{deliveryId}/replay", controller.ReplayDelivery)
+
+	cases := map[string]int{
+		"/webhooks/" + uuid.New().String() + "/deliveries/7/replay": http.StatusAccepted,
+		"/webhooks/" + uuid.New().String() + "/deliveries/8/replay": http.StatusNotFound,
+		"/webhooks/" + uuid.New().String() + "/deliveries/x/replay": http.StatusBadRequest,
+		"/webhooks/not-a-uuid/deliveries/7/replay":                  http.StatusBadRequest,
+	}
+
+	for path, expected := range cases {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, path, nil))
+
+		assert.Equal(t, expected, rr.Code, path)
+	}
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/webhooks/"+uuid.New().String()+"/deliveries/7/replay", nil))
+
+	var response subscription.DeliveryResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &response)
+	assert.Equal(t, "pending", response.Status)
+}
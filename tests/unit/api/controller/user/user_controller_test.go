@@ -4,68 +4,94 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"user-management/api/controller/user"
 	"user-management/api/controller/user/create"
+	"user-management/api/controller/user/get"
+	"user-management/api/controller/user/history"
 	"user-management/api/controller/user/update"
 	"user-management/api/responses"
+	"user-management/bootstrap"
 	"user-management/domain"
+	"user-management/internal/cursor"
 	"user-management/internal/db"
+	"user-management/internal/stream"
 	"user-management/internal/validator"
 	"user-management/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-type mockRepo struct {
-}
+// seedUser stores an active user in repo the way CreateUser would and
+// returns it as repo serves it back.
+func seedUser(t *testing.T, repo domain.UserRepository, firstName string, dateOfBirth time.Time) domain.User {
+	t.Helper()
 
-func (m *mockRepo) Create(ctx context.Context, user *domain.User) (db.CreateUserRow, error) {
-	return db.CreateUserRow{
-		UserID: repository.ToPgUUID(user.UserId),
-		Email:  user.Email,
-		Status: int32(user.Status),
-	}, nil
-}
+	newUser := domain.User{
+		UserId:      uuid.New(),
+		FirstName:   firstName,
+		LastName:    "Lovelace",
+		Email:       fmt.Sprintf("%s.%s@example.com", strings.ToLower(firstName), uuid.NewString()[:8]),
+		Phone:       "+441234567890",
+		DateOfBirth: dateOfBirth,
+		Status:      domain.UserStatusActive,
+	}
+	_, err := repo.Create(context.Background(), &newUser)
+	require.NoError(t, err)
 
-func (m *mockRepo) GetAll(c context.Context) ([]domain.User, error) {
-	var users []domain.User
+	stored, err := repo.GetById(context.Background(), newUser.UserId)
+	require.NoError(t, err)
+	return stored
+}
 
-	users = append(users, domain.User{UserId: uuid.New()})
-	users = append(users, domain.User{UserId: uuid.New()})
+// seedUsers stores count users aged 20 and up, one year apart.
+func seedUsers(t *testing.T, repo domain.UserRepository, count int) []domain.User {
+	t.Helper()
 
-	return users, nil
+	var users []domain.User
+	for i := range count {
+		users = append(users, seedUser(t, repo, fmt.Sprintf("user%02d", i), bornYearsAgo(20+i)))
+	}
+	return users
 }
 
-func (m *mockRepo) GetById(c context.Context, id uuid.UUID) (domain.User, error) {
-	return domain.User{UserId: id}, nil
+// bornYearsAgo returns the date of birth of someone who turned age this
+// January.
+func bornYearsAgo(age int) time.Time {
+	return time.Date(time.Now().Year()-age, time.January, 1, 0, 0, 0, 0, time.UTC)
 }
 
-func (m *mockRepo) Update(c context.Context, id uuid.UUID, user *domain.User) (db.UpdateUserRow, error) {
-	return db.UpdateUserRow{UserID: repository.ToPgUUID(id)}, nil
-}
+// assertNoUsers checks that nothing was written to repo.
+func assertNoUsers(t *testing.T, repo domain.UserRepository) {
+	t.Helper()
 
-func (m *mockRepo) Delete(c context.Context, id uuid.UUID) (uuid.UUID, error) {
-	return uuid.New(), nil
+	counts, err := repo.CountByStatus(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, counts)
 }
 
 func TestCreateUserWithValidData(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
 	}
 
 	createRequest := create.UserRequest{
-		Email:     "s@gmail.com",
-		Phone:     "+94776463619",
-		Age:       2,
-		Status:    1,
-		FirstName: "ss",
-		LastName:  "ss",
+		Email:       "s@gmail.com",
+		Phone:       "+94776463619",
+		DateOfBirth: "1990-04-21",
+		Status:      domain.UserStatusActive,
+		FirstName:   "ss",
+		LastName:    "ss",
 	}
 
 	serializedObject, _ := json.Marshal(createRequest)
@@ -83,13 +109,22 @@ func TestCreateUserWithValidData(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, createRequest.Email, resp.Email)
 	assert.Equal(t, http.StatusCreated, rr.Code)
-	assert.NotEmpty(t, rr.Body.String())
+
+	stored, err := repo.GetById(context.Background(), resp.UserID.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "/users/"+stored.UserId.String(), rr.Header().Get("Location"))
+	assert.Equal(t, createRequest.Email, stored.Email)
+	assert.Equal(t, createRequest.Phone, stored.Phone)
+	assert.Equal(t, createRequest.FirstName, stored.FirstName)
+	assert.Equal(t, createRequest.DateOfBirth, stored.DateOfBirth.Format(time.DateOnly))
+	assert.Equal(t, domain.UserStatusActive, stored.Status)
+	assert.Equal(t, 1, stored.Version)
 }
 
 func TestCreateUserWithInValidJsonData(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
 	}
 
 	createRequest := create.UserRequest{
@@ -106,15 +141,105 @@ func TestCreateUserWithInValidJsonData(t *testing.T) {
 	validator.Init()
 	mockUserController.CreateUser(rr, request)
 
-	var resp responses.Response
+	var resp responses.Problem
 	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
 	assert.NotEmpty(t, resp.Errors)
+	assertNoUsers(t, repo)
+}
+
+func TestCreateUserReportsInvalidFields(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	createRequest := create.UserRequest{
+		Email:       "invalidEmail",
+		Phone:       "+94776463619",
+		DateOfBirth: "1990-04-21",
+		FirstName:   "s",
+		LastName:    "ss",
+	}
+
+	serializedObject, _ := json.Marshal(createRequest)
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(serializedObject))
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.CreateUser(rr, request)
+
+	var resp responses.Problem
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, responses.ProblemContentType, rr.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, resp.Status)
+	assert.Equal(t, "/users", resp.Instance)
+	assert.ElementsMatch(t, []responses.FieldError{
+		{Field: "firstName", Rule: "min", Param: "2", Message: "firstName must be at least 2 characters in length"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+	}, resp.Errors)
+	assertNoUsers(t, repo)
+}
+
+func TestCreateUserTranslatesValidationMessages(t *testing.T) {
+	mockUserController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+	}
+
+	createRequest := create.UserRequest{
+		Email:       "invalidEmail",
+		Phone:       "+94776463619",
+		DateOfBirth: "1990-04-21",
+		FirstName:   "ss",
+		LastName:    "ss",
+	}
+
+	serializedObject, _ := json.Marshal(createRequest)
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(serializedObject))
+	request.Header.Set("Accept-Language", "fr-CH, es-MX;q=0.9, en;q=0.8")
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.CreateUser(rr, request)
+
+	var resp responses.Problem
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, "es", rr.Header().Get("Content-Language"))
+	assert.Len(t, resp.Errors, 1)
+	assert.Equal(t, "email debe ser una dirección de correo electrónico válida", resp.Errors[0].Message)
+}
+
+func TestCreateUserWithMalformedJson(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString("{"))
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.CreateUser(rr, request)
+
+	var resp responses.Problem
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, responses.ProblemTypeBadRequest, resp.Type)
+	assert.Empty(t, resp.Errors)
+	assertNoUsers(t, repo)
 }
 
 func TestGetAllUsers(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	seedUsers(t, repo, 25)
+
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
+		Cursors:        cursor.NewCodec([]byte("secret")),
 	}
 
 	request, _ := http.NewRequest(http.MethodPost, "", nil)
@@ -125,41 +250,207 @@ func TestGetAllUsers(t *testing.T) {
 	rr := httptest.NewRecorder()
 	mockUserController.GetAllUsers(rr, request)
 
-	var resp []domain.User
+	var resp get.UserListResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, get.DefaultPageLimit)
+	assert.Equal(t, int64(25), *resp.Total)
+	assert.NotEmpty(t, resp.NextCursor)
+	assert.Equal(t, get.DefaultPageLimit, resp.Limit)
+}
+
+func TestGetAllUsersPageLinks(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	seedUsers(t, repo, 45)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Cursors:        cursor.NewCodec([]byte("secret")),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?limit=20&offset=20&sort=age&order=desc", nil)
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var resp get.UserListResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, resp.Data, 20)
+	assert.Equal(t, "/users?limit=20&offset=40&order=desc&sort=age", resp.Links.Next)
+	assert.Equal(t, "/users?limit=20&offset=0&order=desc&sort=age", resp.Links.Prev)
+}
+
+func TestGetAllUsersLastPageHasNoNextLink(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	seedUsers(t, repo, 45)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Cursors:        cursor.NewCodec([]byte("secret")),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?limit=20&offset=40", nil)
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var resp get.UserListResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 5)
+	assert.Empty(t, resp.Links.Next)
+	assert.NotEmpty(t, resp.Links.Prev)
+}
+
+func TestGetAllUsersWithInvalidQuery(t *testing.T) {
+	mockUserController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+		Cursors:        cursor.NewCodec([]byte("secret")),
+	}
+
+	for _, query := range []string{"limit=0", "limit=101", "offset=abc", "sort=phone", "order=up", "status=9", "cursor=forged", "cursor=a.b&offset=20", "createdAfter=yesterday", "minAge=40&maxAge=30"} {
+		request, _ := http.NewRequest(http.MethodGet, "/users?"+query, nil)
+		validator.Init()
+
+		rr := httptest.NewRecorder()
+		mockUserController.GetAllUsers(rr, request)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestGetAllUsersWithSingleAge(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	users := seedUsers(t, repo, 15)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Cursors:        cursor.NewCodec([]byte("secret")),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?minAge=30&maxAge=30", nil)
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var resp get.UserListResponse
 	err := json.Unmarshal(rr.Body.Bytes(), &resp)
 	assert.NoError(t, err)
-	assert.NotEmpty(t, resp)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, users[10].UserId, resp.Data[0].UserId)
+		assert.Equal(t, 30, resp.Data[0].Age)
+	}
+}
+
+func TestGetAllUsersWithCursor(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	seedUsers(t, repo, 12)
+
+	codec := cursor.NewCodec([]byte("secret"))
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Cursors:        codec,
+	}
+	validator.Init()
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?limit=5&sort=age&order=desc", nil)
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var first get.UserListResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &first))
+	require.NotEmpty(t, first.NextCursor)
+
+	request, _ = http.NewRequest(http.MethodGet, "/users?limit=5&cursor="+first.NextCursor, nil)
+	rr = httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var resp get.UserListResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Nil(t, resp.Total)
+	assert.NotEmpty(t, resp.NextCursor)
+	assert.Contains(t, resp.Links.Next, "cursor="+resp.NextCursor)
+
+	var ages []int
+	for _, found := range append(first.Data, resp.Data...) {
+		ages = append(ages, found.Age)
+	}
+	assert.Equal(t, []int{31, 30, 29, 28, 27, 26, 25, 24, 23, 22}, ages)
+
+	next, err := codec.Decode(resp.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.UserSortByAge, next.SortBy)
+	assert.True(t, next.SortDesc)
+	assert.Equal(t, resp.Data[4].UserId, next.LastUserId)
+}
+
+func TestGetAllUsersWithCursorForDifferentSort(t *testing.T) {
+	codec := cursor.NewCodec([]byte("secret"))
+	mockUserController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+		Cursors:        codec,
+	}
+
+	token := codec.Encode(domain.UserCursor{
+		SortBy:     domain.UserSortByAge,
+		LastValue:  "1990-04-21",
+		LastUserId: uuid.New(),
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?sort=email&cursor="+token, nil)
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
 func TestGetUserById(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", time.Date(1990, time.December, 10, 0, 0, 0, 0, time.UTC))
+
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
 	}
 
 	r := chi.NewRouter()
 	r.Get("/users/{id}", mockUserController.GetUserById)
 
-	id := uuid.New().String()
+	id := ada.UserId.String()
 	request, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
 
 	validator.Init()
 
-	var resp domain.User
-
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, request)
 
-	err := json.Unmarshal(rr.Body.Bytes(), &resp)
-
-	assert.NoError(t, err)
-	assert.NotEmpty(t, resp)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, id, body["userId"])
+	assert.Equal(t, "Ada", body["firstName"])
+	assert.Equal(t, ada.Email, body["email"])
+	assert.Equal(t, "1990-12-10", body["dateOfBirth"])
+	assert.Contains(t, body, "createdAt")
+	assert.NotContains(t, body, "UserId")
+	assert.NotContains(t, body, "Version")
+	assert.NotContains(t, body, "version")
 }
 
 func TestUpdateUser(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
 	}
 
 	updateRequest := update.UserRequest{
@@ -172,8 +463,7 @@ func TestUpdateUser(t *testing.T) {
 	r := chi.NewRouter()
 	r.Put("/users/{id}", mockUserController.UpdateUser)
 
-	id := uuid.New().String()
-	request, _ := http.NewRequest(http.MethodPut, "/users/"+id, requestBody)
+	request, _ := http.NewRequest(http.MethodPut, "/users/"+ada.UserId.String(), requestBody)
 
 	validator.Init()
 
@@ -182,12 +472,49 @@ func TestUpdateUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, "sample@gmail.com", stored.Email)
+	assert.Equal(t, "Ada", stored.FirstName)
+	assert.Equal(t, 2, stored.Version)
+}
+
+func TestUpdateUserRejectsStatus(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Put("/users/{id}", mockUserController.UpdateUser)
+	validator.Init()
+
+	for _, body := range []string{`{"firstName":"Grace","status":"suspended"}`, `{"status":"active"}`, `{"status":null}`} {
+		request, _ := http.NewRequest(http.MethodPut, "/users/"+ada.UserId.String(), bytes.NewBufferString(body))
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		var problem responses.Problem
+		_ = json.Unmarshal(rr.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, body)
+		assert.Equal(t, "status cannot be changed here; use the status endpoints", problem.Detail, body)
+	}
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
 }
 
 func TestUpdateUserWithInvalidEmail(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
 	mockUserController := user.UserController{
-		&mockRepo{},
-		nil,
+		UserRepository: repo,
 	}
 
 	updateRequest := update.UserRequest{
@@ -200,8 +527,7 @@ func TestUpdateUserWithInvalidEmail(t *testing.T) {
 	r := chi.NewRouter()
 	r.Put("/users/{id}", mockUserController.UpdateUser)
 
-	id := uuid.New().String()
-	request, _ := http.NewRequest(http.MethodPut, "/users/"+id, requestBody)
+	request, _ := http.NewRequest(http.MethodPut, "/users/"+ada.UserId.String(), requestBody)
 
 	validator.Init()
 
@@ -209,4 +535,788 @@ func TestUpdateUserWithInvalidEmail(t *testing.T) {
 	r.ServeHTTP(rr, request)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
+}
+
+// failingRepo fails every Create and GetById with err.
+type failingRepo struct {
+	domain.UserRepository
+	err error
+}
+
+func (f *failingRepo) Create(ctx context.Context, user *domain.User) (db.CreateUserRow, error) {
+	return db.CreateUserRow{}, f.err
+}
+
+func (f *failingRepo) GetById(c context.Context, id uuid.UUID) (domain.User, error) {
+	return domain.User{}, f.err
+}
+
+func TestGetUserByIdWithMalformedId(t *testing.T) {
+	mockUserController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+	}
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}", mockUserController.GetUserById)
+
+	request, _ := http.NewRequest(http.MethodGet, "/users/not-a-uuid", nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetUserByIdMapsDomainErrors(t *testing.T) {
+	cases := map[error]int{
+		domain.NewNotFoundError("user not found", nil):             http.StatusNotFound,
+		domain.NewUnavailableError("database is unavailable", nil): http.StatusServiceUnavailable,
+		domain.NewTimeoutError("database did not respond", nil):    http.StatusGatewayTimeout,
+		errors.New("connection reset by peer: secret details"):     http.StatusInternalServerError,
+	}
+
+	for repoErr, expectedStatus := range cases {
+		mockUserController := user.UserController{
+			UserRepository: &failingRepo{UserRepository: repository.NewMemoryUserRepository(), err: repoErr},
+		}
+
+		r := chi.NewRouter()
+		r.Get("/users/{id}", mockUserController.GetUserById)
+
+		request, _ := http.NewRequest(http.MethodGet, "/users/"+uuid.New().String(), nil)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		var resp responses.Problem
+		_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+		assert.Equal(t, expectedStatus, rr.Code)
+		assert.Equal(t, expectedStatus, resp.Status)
+		assert.NotContains(t, resp.Detail, "secret details")
+	}
+}
+
+func TestGetUserByIdWhenClientCancels(t *testing.T) {
+	// Postgres reports the statement cancelled on the client's behalf as a
+	// timeout.
+	mockUserController := user.UserController{
+		UserRepository: &failingRepo{
+			UserRepository: repository.NewMemoryUserRepository(),
+			err:            domain.NewTimeoutError("database did not respond in time", nil),
+		},
+	}
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}", mockUserController.GetUserById)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/users/"+uuid.New().String(), nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, responses.StatusClientClosedRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), responses.ProblemTypeCanceled)
+}
+
+func TestCreateUserWithDuplicateEmail(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	existing := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	createRequest := create.UserRequest{
+		Email:       existing.Email,
+		Phone:       "+94776463619",
+		DateOfBirth: "1990-04-21",
+		Status:      domain.UserStatusActive,
+		FirstName:   "ss",
+		LastName:    "ss",
+	}
+
+	serializedObject, _ := json.Marshal(createRequest)
+	request, _ := http.NewRequest(http.MethodPost, "", bytes.NewBuffer(serializedObject))
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.CreateUser(rr, request)
+
+	var resp responses.Problem
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "a user with this email already exists", resp.Detail)
+
+	counts, err := repo.CountByStatus(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.UserStatus]int64{domain.UserStatusActive: 1}, counts)
+}
+
+func TestGetUserByIdReturnsETag(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}", mockUserController.GetUserById)
+
+	id := ada.UserId.String()
+	request, _ := http.NewRequest(http.MethodGet, "/users/"+id, nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+
+	request, _ = http.NewRequest(http.MethodGet, "/users/"+id, nil)
+	request.Header.Set("If-None-Match", `"1"`)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusNotModified, rr.Code)
+	assert.Empty(t, rr.Body.String())
+}
+
+func TestGetUserByIdWithIfNoneMatchList(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}", mockUserController.GetUserById)
+
+	cases := map[string]int{
+		`"1"`:             http.StatusNotModified,
+		`W/"1"`:           http.StatusNotModified,
+		`"0", "1"`:        http.StatusNotModified,
+		`"a,b" , W/"1"`:   http.StatusNotModified,
+		`*`:               http.StatusNotModified,
+		`"2"`:             http.StatusOK,
+		`"2", W/"3"`:      http.StatusOK,
+		`"11"`:            http.StatusOK,
+		`garbage, "unclo`: http.StatusOK,
+		`1`:               http.StatusOK,
+	}
+
+	for ifNoneMatch, expectedStatus := range cases {
+		request, _ := http.NewRequest(http.MethodGet, "/users/"+ada.UserId.String(), nil)
+		request.Header.Set("If-None-Match", ifNoneMatch)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, expectedStatus, rr.Code, ifNoneMatch)
+	}
+}
+
+func TestUpdateUserWithIfMatch(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Put("/users/{id}", mockUserController.UpdateUser)
+	validator.Init()
+
+	cases := map[string]int{
+		`"1"`:     http.StatusOK,
+		`*`:       http.StatusOK,
+		`"7"`:     http.StatusPreconditionFailed,
+		`W/"1"`:   http.StatusPreconditionFailed,
+		`garbage`: http.StatusPreconditionFailed,
+	}
+
+	for ifMatch, expectedStatus := range cases {
+		ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+		request, _ := http.NewRequest(http.MethodPut, "/users/"+ada.UserId.String(), bytes.NewBufferString(`{"firstName":"Updated"}`))
+		request.Header.Set("If-Match", ifMatch)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, expectedStatus, rr.Code, ifMatch)
+
+		stored, err := repo.GetById(context.Background(), ada.UserId)
+		require.NoError(t, err)
+		if expectedStatus == http.StatusOK {
+			assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+			assert.Equal(t, "Updated", stored.FirstName, ifMatch)
+			assert.Equal(t, 2, stored.Version, ifMatch)
+		} else {
+			assert.Equal(t, ada, stored, ifMatch)
+		}
+	}
+}
+
+func TestUpdateAndDeleteRequireIfMatchWhenConfigured(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Env:            &bootstrap.Env{RequireIfMatch: true},
+	}
+
+	r := chi.NewRouter()
+	r.Put("/users/{id}", mockUserController.UpdateUser)
+	r.Delete("/users/{id}", mockUserController.DeleteUser)
+	validator.Init()
+
+	request, _ := http.NewRequest(http.MethodPut, "/users/"+ada.UserId.String(), bytes.NewBufferString(`{"firstName":"Updated"}`))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+	assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+
+	request, _ = http.NewRequest(http.MethodDelete, "/users/"+ada.UserId.String(), nil)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+	assert.Equal(t, http.StatusPreconditionRequired, rr.Code)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
+
+	request, _ = http.NewRequest(http.MethodDelete, "/users/"+ada.UserId.String(), nil)
+	request.Header.Set("If-Match", `"1"`)
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+
+	_, err = repo.GetById(context.Background(), ada.UserId)
+	assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+}
+
+// seedAda stores the fully populated user the patch tests start from.
+func seedAda(t *testing.T, repo domain.UserRepository) domain.User {
+	t.Helper()
+	return seedUser(t, repo, "Ada", time.Date(1990, time.December, 10, 0, 0, 0, 0, time.UTC))
+}
+
+func patchUser(repo domain.UserRepository, id uuid.UUID, contentType string, body string) *httptest.ResponseRecorder {
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Patch("/users/{id}", mockUserController.PatchUser)
+	validator.Init()
+
+	request, _ := http.NewRequest(http.MethodPatch, "/users/"+id.String(), bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", contentType)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+	return rr
+}
+
+func TestPatchUserWithMergePatch(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/merge-patch+json", `{"lastName":"Byron","status":"active"}`)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", stored.FirstName)
+	assert.Equal(t, "Byron", stored.LastName)
+	assert.Equal(t, ada.Email, stored.Email)
+	assert.Equal(t, domain.UserStatusActive, stored.Status)
+	assert.Equal(t, 2, stored.Version)
+}
+
+func TestPatchUserRejectsStatusChange(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	cases := map[string]string{
+		"application/merge-patch+json": `{"lastName":"Byron","status":"suspended"}`,
+		"application/json-patch+json":  `[{"op":"test","path":"/status","value":"active"},{"op":"replace","path":"/status","value":"suspended"}]`,
+	}
+
+	for contentType, body := range cases {
+		rr := patchUser(repo, ada.UserId, contentType, body)
+
+		var problem responses.Problem
+		_ = json.Unmarshal(rr.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code, contentType)
+		assert.Equal(t, "status cannot be changed here; use the status endpoints", problem.Detail, contentType)
+	}
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
+}
+
+func TestPatchUserWithJSONPatch(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/json-patch+json",
+		`[{"op":"test","path":"/email","value":"`+ada.Email+`"},{"op":"replace","path":"/dateOfBirth","value":"1991-12-10"}]`)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(1991, time.December, 10, 0, 0, 0, 0, time.UTC), stored.DateOfBirth)
+}
+
+func TestPatchUserWithFailingTestOperation(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/json-patch+json",
+		`[{"op":"test","path":"/email","value":"someone@example.com"},{"op":"replace","path":"/dateOfBirth","value":"1991-12-10"}]`)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
+}
+
+func TestPatchUserValidatesPatchedUser(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/merge-patch+json", `{"firstName":null}`)
+
+	var problem responses.Problem
+	_ = json.Unmarshal(rr.Body.Bytes(), &problem)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Len(t, problem.Errors, 1)
+	assert.Equal(t, "firstName", problem.Errors[0].Field)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, ada, stored)
+}
+
+func TestPatchUserRejectsUnknownFields(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/merge-patch+json", `{"nickname":"ada"}`)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPatchUserRejectsUnsupportedContentType(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedAda(t, repo)
+
+	rr := patchUser(repo, ada.UserId, "application/json", `{"lastName":"Byron"}`)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	assert.Contains(t, rr.Header().Get("Accept-Patch"), "application/merge-patch+json")
+}
+
+func TestRestoreUser(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+	_, err := repo.Delete(context.Background(), ada.UserId, 0)
+	require.NoError(t, err)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/restore", mockUserController.RestoreUser)
+
+	request, _ := http.NewRequest(http.MethodPost, "/users/"+ada.UserId.String()+"/restore", nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Version)
+}
+
+func TestPurgeUser(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/purge", mockUserController.PurgeUser)
+
+	request, _ := http.NewRequest(http.MethodPost, "/users/"+ada.UserId.String()+"/purge", nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	_, err := repo.GetHistory(context.Background(), ada.UserId)
+	assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+}
+
+func TestGetAllUsersSortedByCreatedAt(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	users := seedUsers(t, repo, 3)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+		Cursors:        cursor.NewCodec([]byte("secret")),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/users?sort=createdAt&order=desc&createdAfter=2024-01-01T00:00:00Z", nil)
+	validator.Init()
+
+	rr := httptest.NewRecorder()
+	mockUserController.GetAllUsers(rr, request)
+
+	var resp get.UserListResponse
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, resp.Data, len(users))
+	for i := 1; i < len(resp.Data); i++ {
+		assert.False(t, resp.Data[i].CreatedAt.After(resp.Data[i-1].CreatedAt))
+	}
+}
+
+func TestCreateUserRejectsInvalidDateOfBirth(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+	validator.Init()
+
+	cases := map[string]string{
+		"21/04/1990": "datetime",
+		time.Now().AddDate(0, 0, 2).Format(time.DateOnly):  "notfuture",
+		time.Now().AddDate(-5, 0, 0).Format(time.DateOnly): "minage",
+	}
+
+	for dateOfBirth, rule := range cases {
+		serializedObject, _ := json.Marshal(create.UserRequest{
+			Email:       "s@gmail.com",
+			FirstName:   "Fname",
+			LastName:    "Lname",
+			Phone:       "+12345678901",
+			DateOfBirth: dateOfBirth,
+		})
+
+		request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBuffer(serializedObject))
+		rr := httptest.NewRecorder()
+		mockUserController.CreateUser(rr, request)
+
+		var problem responses.Problem
+		_ = json.Unmarshal(rr.Body.Bytes(), &problem)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, dateOfBirth)
+		if assert.Len(t, problem.Errors, 1, dateOfBirth) {
+			assert.Equal(t, "dateOfBirth", problem.Errors[0].Field)
+			assert.Equal(t, rule, problem.Errors[0].Rule)
+		}
+	}
+
+	assertNoUsers(t, repo)
+}
+
+func TestUserStatusActions(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/activate", mockUserController.ActivateUser)
+	r.Post("/users/{id}/suspend", mockUserController.SuspendUser)
+	r.Post("/users/{id}/deactivate", mockUserController.DeactivateUser)
+	validator.Init()
+
+	// Each action applies to the status the one before it left behind.
+	cases := []struct {
+		action   string
+		body     string
+		expected int
+		status   domain.UserStatus
+	}{
+		{action: "suspend", body: `{"reason":"chargeback under review"}`, expected: http.StatusOK, status: domain.UserStatusSuspended},
+		{action: "deactivate", body: `{"reason":"account closed by owner"}`, expected: http.StatusOK, status: domain.UserStatusDeactivated},
+		{action: "activate", body: `{"reason":"owner asked to reopen"}`, expected: http.StatusOK, status: domain.UserStatusActive},
+		{action: "activate", body: `{"reason":"already active"}`, expected: http.StatusOK, status: domain.UserStatusActive},
+		{action: "suspend", body: `{}`, expected: http.StatusBadRequest, status: domain.UserStatusActive},
+		{action: "suspend", body: `not json`, expected: http.StatusBadRequest, status: domain.UserStatusActive},
+	}
+
+	for _, tc := range cases {
+		request, _ := http.NewRequest(http.MethodPost, "/users/"+ada.UserId.String()+"/"+tc.action, bytes.NewBufferString(tc.body))
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, tc.expected, rr.Code, tc.action+" "+tc.body)
+
+		stored, err := repo.GetById(context.Background(), ada.UserId)
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, stored.Status, tc.action+" "+tc.body)
+	}
+}
+
+func TestSuspendLockedUserConflicts(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+	_, err := repo.ChangeStatus(context.Background(), ada.UserId, domain.UserStatusLocked, "too many failed sign-ins", 0)
+	require.NoError(t, err)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+	validator.Init()
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/suspend", mockUserController.SuspendUser)
+
+	request, _ := http.NewRequest(http.MethodPost, "/users/"+ada.UserId.String()+"/suspend", bytes.NewBufferString(`{"reason":"spam"}`))
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	var problem responses.Problem
+	_ = json.Unmarshal(rr.Body.Bytes(), &problem)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "user cannot go from locked to suspended", problem.Detail)
+
+	stored, err := repo.GetById(context.Background(), ada.UserId)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusLocked, stored.Status)
+}
+
+// seedRenamedUser stores Ada and then renames her Grace, leaving two
+// revisions.
+func seedRenamedUser(t *testing.T, repo domain.UserRepository) domain.User {
+	t.Helper()
+
+	ada := seedUser(t, repo, "Ada", bornYearsAgo(36))
+	_, err := repo.Update(context.Background(), ada.UserId, &domain.User{FirstName: "Grace"})
+	require.NoError(t, err)
+	return ada
+}
+
+func TestGetUserHistory(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedRenamedUser(t, repo)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}/history", mockUserController.GetUserHistory)
+
+	request, _ := http.NewRequest(http.MethodGet, "/users/"+ada.UserId.String()+"/history", nil)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, request)
+
+	var response history.UserHistoryResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if assert.Len(t, response.Revisions, 2) {
+		assert.Equal(t, "created", response.Revisions[0].Operation)
+		assert.Equal(t, "updated", response.Revisions[1].Operation)
+		assert.Equal(t, domain.FieldChange{From: "Ada", To: "Grace"}, response.Revisions[1].Changes["firstName"])
+	}
+}
+
+func TestGetUserSnapshot(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	ada := seedRenamedUser(t, repo)
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	mockUserController := user.UserController{
+		UserRepository: repo,
+	}
+	validator.Init()
+
+	r := chi.NewRouter()
+	r.Get("/users/{id}/snapshot", mockUserController.GetUserSnapshot)
+
+	cases := []struct {
+		query     string
+		expected  int
+		firstName string
+	}{
+		{query: "?revision=1", expected: http.StatusOK, firstName: "Ada"},
+		{query: "?revision=2", expected: http.StatusOK, firstName: "Grace"},
+		{query: "?at=" + now, expected: http.StatusOK, firstName: "Grace"},
+		{query: "?at=2000-01-02T15:04:05Z", expected: http.StatusNotFound},
+		{query: "?revision=3", expected: http.StatusNotFound},
+		{query: "", expected: http.StatusBadRequest},
+		{query: "?revision=1&at=2026-01-02T15:04:05Z", expected: http.StatusBadRequest},
+		{query: "?revision=0", expected: http.StatusBadRequest},
+		{query: "?at=yesterday", expected: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		request, _ := http.NewRequest(http.MethodGet, "/users/"+ada.UserId.String()+"/snapshot"+tc.query, nil)
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, request)
+
+		assert.Equal(t, tc.expected, rr.Code, tc.query)
+
+		if tc.expected == http.StatusOK {
+			var response history.SnapshotResponse
+			_ = json.Unmarshal(rr.Body.Bytes(), &response)
+			assert.Equal(t, tc.firstName, response.FirstName, tc.query)
+		}
+	}
+}
+
+func TestStreamUserEvents(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	broker := stream.NewBroker(10)
+	broker.Publish(stream.Event{ID: "1", Type: "user.created", UserID: alice, Data: []byte(`{"id":"1"}`)})
+	broker.Publish(stream.Event{ID: "2", Type: "user.updated", UserID: alice, Data: []byte(`{"id":"2"}`)})
+	broker.Publish(stream.Event{ID: "3", Type: "user.deleted", UserID: alice, Data: []byte(`{"id":"3"}`)})
+	broker.Publish(stream.Event{ID: "4", Type: "user.deleted", UserID: bob, Data: []byte(`{"id":"4"}`)})
+	// End the streams once the backlog is written.
+	broker.Close()
+
+	mockUserController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+		Events:         broker,
+	}
+	validator.Init()
+
+	r := chi.NewRouter()
+	r.Get("/users/events", mockUserController.StreamUserEvents)
+
+	cases := []struct {
+		name        string
+		query       string
+		lastEventID string
+		expected    int
+		body        string
+	}{
+		{name: "NoBacklogWithoutLastEventID", expected: http.StatusOK, body: ""},
+		{
+			name:        "ResumesAfterLastEventID",
+			lastEventID: "2",
+			expected:    http.StatusOK,
+			body:        "id: 3\nevent: user.deleted\ndata: {\"id\":\"3\"}\n\nid: 4\nevent: user.deleted\ndata: {\"id\":\"4\"}\n\n",
+		},
+		{
+			name:        "FiltersByTypeAndUser",
+			query:       "?type=user.created,user.deleted&userId=" + alice.String(),
+			lastEventID: "1",
+			expected:    http.StatusOK,
+			body:        "id: 3\nevent: user.deleted\ndata: {\"id\":\"3\"}\n\n",
+		},
+		{
+			name:        "ResetsForForgottenEvent",
+			query:       "?type=user.created",
+			lastEventID: "unknown",
+			expected:    http.StatusOK,
+			body:        "event: reset\ndata: {}\n\nid: 1\nevent: user.created\ndata: {\"id\":\"1\"}\n\n",
+		},
+		{name: "RejectsUnknownType", query: "?type=user.renamed", expected: http.StatusBadRequest},
+		{name: "RejectsInvalidUserId", query: "?userId=nope", expected: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "/users/events"+tc.query, nil)
+			if tc.lastEventID != "" {
+				request.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, request)
+
+			assert.Equal(t, tc.expected, rr.Code)
+			if tc.expected == http.StatusOK {
+				assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+				assert.Equal(t, tc.body, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserRoundTripWithMemoryRepository(t *testing.T) {
+	userController := user.UserController{
+		UserRepository: repository.NewMemoryUserRepository(),
+	}
+	validator.Init()
+
+	r := chi.NewRouter()
+	r.Post("/users", userController.CreateUser)
+	r.Get("/users/{id}", userController.GetUserById)
+	r.Delete("/users/{id}", userController.DeleteUser)
+
+	createRequest := create.UserRequest{
+		Email:       "ada@example.com",
+		Phone:       "+94776463619",
+		DateOfBirth: "1990-04-21",
+		FirstName:   "Ada",
+		LastName:    "Lovelace",
+	}
+	body, _ := json.Marshal(createRequest)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusCreated, rr.Code)
+
+	location := rr.Header().Get("Location")
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, location, nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var found get.UserResponseDto
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &found))
+	assert.Equal(t, "Ada", found.FirstName)
+	assert.Equal(t, domain.UserStatusActive, found.Status)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, location, nil))
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, location, nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
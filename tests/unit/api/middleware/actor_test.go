@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorIsAddedToContext(t *testing.T) {
+	var actor string
+	handler := middleware.Actor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = domain.ActorFrom(r.Context())
+	}))
+
+	request, _ := http.NewRequest(http.MethodPost, "/users", nil)
+	request.Header.Set(middleware.ActorHeader, " support-agent ")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Equal(t, "support-agent", actor)
+
+	request, _ = http.NewRequest(http.MethodPost, "/users", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	assert.Empty(t, actor)
+}
+
+func TestActorRejectsOverlongHeader(t *testing.T) {
+	handler := middleware.Actor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request, _ := http.NewRequest(http.MethodPost, "/users", nil)
+	request.Header.Set(middleware.ActorHeader, strings.Repeat("a", 256))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/api/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cases := []struct {
+		name          string
+		token         string
+		authorization string
+		expected      int
+	}{
+		{name: "valid token", token: "secret", authorization: "Bearer secret", expected: http.StatusNoContent},
+		{name: "missing header", token: "secret", authorization: "", expected: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "secret", authorization: "Basic secret", expected: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", authorization: "Bearer guess", expected: http.StatusForbidden},
+		{name: "no token configured", token: "", authorization: "Bearer anything", expected: http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		request, _ := http.NewRequest(http.MethodPost, "/users/1/purge", nil)
+		if tc.authorization != "" {
+			request.Header.Set("Authorization", tc.authorization)
+		}
+
+		rr := httptest.NewRecorder()
+		middleware.RequireAdmin(tc.token)(ok).ServeHTTP(rr, request)
+
+		assert.Equal(t, tc.expected, rr.Code, tc.name)
+	}
+}
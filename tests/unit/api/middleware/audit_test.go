@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/internal/audit"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingAuditStore struct {
+	entries []audit.Entry
+}
+
+func (s *recordingAuditStore) Append(c context.Context, entry audit.Entry) (audit.Entry, error) {
+	s.entries = append(s.entries, entry)
+	return entry, nil
+}
+
+func (s *recordingAuditStore) List(c context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	return s.entries, nil
+}
+
+func newAuditedRouter(store audit.Store) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(chimiddleware.RequestID)
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Audit(store))
+		r.Use(middleware.Actor)
+
+		r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+		r.Put("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		})
+		r.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "/users/9b0a3a36-2d4f-4f38-9d55-8d6d41b6f0a2")
+			w.WriteHeader(http.StatusCreated)
+		})
+	})
+	return router
+}
+
+func TestAuditRecordsMutatingCalls(t *testing.T) {
+	store := &recordingAuditStore{}
+	router := newAuditedRouter(store)
+	target := uuid.New()
+
+	request := httptest.NewRequest(http.MethodPut, "/users/"+target.String(), nil)
+	request.RemoteAddr = "203.0.113.7:52114"
+	request.Header.Set(middleware.ActorHeader, "support")
+	request.Header.Set(chimiddleware.RequestIDHeader, "req-42")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Len(t, store.entries, 1)
+	entry := store.entries[0]
+	assert.Equal(t, "support", entry.Actor)
+	assert.Equal(t, "203.0.113.7", entry.SourceIP)
+	assert.Equal(t, "req-42", entry.RequestID)
+	assert.Equal(t, http.MethodPut, entry.Method)
+	assert.Equal(t, "/users/{id}", entry.Route)
+	assert.Equal(t, target, entry.TargetUser)
+	assert.Equal(t, http.StatusPreconditionFailed, entry.Status)
+	assert.Equal(t, audit.OutcomeFailure, entry.Outcome)
+	assert.False(t, entry.OccurredAt.IsZero())
+}
+
+func TestAuditTakesCreatedUserFromLocation(t *testing.T) {
+	store := &recordingAuditStore{}
+	router := newAuditedRouter(store)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	assert.Len(t, store.entries, 1)
+	assert.Equal(t, "9b0a3a36-2d4f-4f38-9d55-8d6d41b6f0a2", store.entries[0].TargetUser.String())
+	assert.Equal(t, audit.OutcomeSuccess, store.entries[0].Outcome)
+	assert.NotEmpty(t, store.entries[0].RequestID)
+}
+
+func TestAuditRecordsCallsActorRejects(t *testing.T) {
+	store := &recordingAuditStore{}
+	router := newAuditedRouter(store)
+
+	request := httptest.NewRequest(http.MethodPut, "/users/"+uuid.New().String(), nil)
+	request.Header.Set(middleware.ActorHeader, strings.Repeat("a", 256))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, request)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	if assert.Len(t, store.entries, 1) {
+		assert.Empty(t, store.entries[0].Actor)
+		assert.Equal(t, http.StatusBadRequest, store.entries[0].Status)
+		assert.Equal(t, audit.OutcomeFailure, store.entries[0].Outcome)
+	}
+}
+
+func TestAuditSkipsReads(t *testing.T) {
+	store := &recordingAuditStore{}
+	router := newAuditedRouter(store)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/"+uuid.New().String(), nil))
+
+	assert.Empty(t, store.entries)
+}
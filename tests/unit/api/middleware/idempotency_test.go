@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"user-management/api/middleware"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	responses map[string]domain.IdempotentResponse
+	claims    map[string]uuid.UUID
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{responses: map[string]domain.IdempotentResponse{}, claims: map[string]uuid.UUID{}}
+}
+
+func (m *memoryIdempotencyStore) Claim(c context.Context, key string, requestHash string, ttl time.Duration, lease time.Duration) (uuid.UUID, domain.IdempotentResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stored, ok := m.responses[key]; ok {
+		return uuid.Nil, stored, nil
+	}
+
+	claim := uuid.New()
+	m.responses[key] = domain.IdempotentResponse{RequestHash: requestHash}
+	m.claims[key] = claim
+	return claim, domain.IdempotentResponse{}, nil
+}
+
+func (m *memoryIdempotencyStore) Complete(c context.Context, key string, claim uuid.UUID, response domain.IdempotentResponse) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.claims[key] != claim {
+		return domain.NewConflictError("idempotency key was taken over by another request", nil)
+	}
+	m.responses[key] = response
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(c context.Context, key string, claim uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.claims[key] == claim && m.responses[key].StatusCode == 0 {
+		delete(m.responses, key)
+		delete(m.claims, key)
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyStore) DeleteExpired(c context.Context) (int64, error) {
+	return 0, nil
+}
+
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"call":` + string(rune('0'+*calls)) + `}`))
+	})
+}
+
+func post(handler http.Handler, key string, body string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+	if key != "" {
+		request.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, request)
+	return rr
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour, time.Minute, 1024)(countingHandler(&calls, http.StatusCreated))
+
+	first := post(handler, "key-1", `{"email":"a@b.com"}`)
+	second := post(handler, "key-1", `{"email":"a@b.com"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, `"1"`, second.Header().Get("ETag"))
+	assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+}
+
+func TestIdempotencyRejectsKeyReuseWithDifferentBody(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour, time.Minute, 1024)(countingHandler(&calls, http.StatusCreated))
+
+	post(handler, "key-1", `{"email":"a@b.com"}`)
+	rr := post(handler, "key-1", `{"email":"other@b.com"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestIdempotencyReportsRequestInProgress(t *testing.T) {
+	var handler http.Handler
+	var retry *httptest.ResponseRecorder
+
+	handler = middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour, time.Minute, 1024)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			retry = post(handler, "key-1", `{}`)
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+	post(handler, "key-1", `{}`)
+
+	assert.Equal(t, http.StatusConflict, retry.Code)
+}
+
+func TestIdempotencyDoesNotStoreServerErrors(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour, time.Minute, 1024)(countingHandler(&calls, http.StatusServiceUnavailable))
+
+	post(handler, "key-1", `{}`)
+	post(handler, "key-1", `{}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyWithoutKeyPassesThrough(t *testing.T) {
+	calls := 0
+	handler := middleware.Idempotency(newMemoryIdempotencyStore(), time.Hour, time.Minute, 1024)(countingHandler(&calls, http.StatusCreated))
+
+	post(handler, "", `{}`)
+	post(handler, "", `{}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyRejectsOversizedBody(t *testing.T) {
+	calls := 0
+	store := newMemoryIdempotencyStore()
+	handler := middleware.Idempotency(store, time.Hour, time.Minute, 16)(countingHandler(&calls, http.StatusCreated))
+
+	rr := post(handler, "key-1", `{"firstName":"much too long"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Contains(t, rr.Body.String(), "/problems/payload-too-large")
+	assert.Equal(t, 0, calls)
+	assert.Empty(t, store.responses)
+}
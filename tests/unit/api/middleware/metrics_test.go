@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/internal/metrics"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsLabelRequestsByRoutePattern(t *testing.T) {
+	observed := metrics.New()
+
+	router := chi.NewRouter()
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Metrics(observed.HTTP))
+		r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "id") == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		})
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/missing", "/unknown/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP user_management_http_requests_total Requests handled, by method, route pattern and status code.
+# TYPE user_management_http_requests_total counter
+user_management_http_requests_total{method="GET",route="/users/{id}",status="200"} 2
+user_management_http_requests_total{method="GET",route="/users/{id}",status="404"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(observed.Registry, strings.NewReader(expected), "user_management_http_requests_total"))
+}
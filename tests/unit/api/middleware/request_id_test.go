@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = chimiddleware.GetReqID(r.Context())
+	}))
+
+	cases := []struct {
+		name       string
+		header     string
+		propagated bool
+	}{
+		{name: "Missing"},
+		{name: "Propagated", header: "upstream-3f2a9c", propagated: true},
+		{name: "TooLong", header: strings.Repeat("a", 129)},
+		{name: "ControlCharacters", header: "abc\ninjected=1"},
+		{name: "Spaces", header: "abc def"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tc.header != "" {
+				request.Header.Set(middleware.RequestIDHeader, tc.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, request)
+
+			assert.Equal(t, seen, rr.Header().Get(middleware.RequestIDHeader))
+			if tc.propagated {
+				assert.Equal(t, tc.header, seen)
+			} else {
+				assert.NoError(t, uuid.Validate(seen))
+			}
+		})
+	}
+}
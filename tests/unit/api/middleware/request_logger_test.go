@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/api/responses"
+	"user-management/internal/logging"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const loggedUserID = "5b0d6f7e-7c1a-4a55-9b8e-2f6f1c1a2b3c"
+
+func logRecords(t *testing.T, out *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func loggedRouter(out *bytes.Buffer, handler http.HandlerFunc) http.Handler {
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.RequestLogger(slog.New(slog.NewJSONHandler(out, nil))))
+		r.Get("/users/{id}", handler)
+	})
+	return router
+}
+
+func TestRequestLoggerLogsHandledRequest(t *testing.T) {
+	var out bytes.Buffer
+	router := loggedRouter(&out, func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("loading user")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users/"+loggedUserID, nil)
+	request.Header.Set(middleware.RequestIDHeader, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	records := logRecords(t, &out)
+	assert.Len(t, records, 2)
+
+	handlerRecord, requestRecord := records[0], records[1]
+
+	assert.Equal(t, "loading user", handlerRecord["msg"])
+	assert.Equal(t, "req-1", handlerRecord["request_id"])
+	assert.Equal(t, "/users/{id}", handlerRecord["route"])
+
+	assert.Equal(t, "request", requestRecord["msg"])
+	assert.Equal(t, "INFO", requestRecord["level"])
+	assert.Equal(t, "req-1", requestRecord["request_id"])
+	assert.Equal(t, http.MethodGet, requestRecord["method"])
+	assert.Equal(t, "/users/{id}", requestRecord["route"])
+	assert.Equal(t, float64(http.StatusNoContent), requestRecord["status"])
+	assert.Equal(t, loggedUserID, requestRecord["user_id"])
+	assert.Contains(t, requestRecord, "latency")
+}
+
+func TestRequestLoggerCorrelatesRepositoryErrors(t *testing.T) {
+	var out bytes.Buffer
+	router := loggedRouter(&out, func(w http.ResponseWriter, r *http.Request) {
+		responses.WriteError(w, r, errors.New("connection reset"))
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/"+loggedUserID, nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	requestID := rr.Header().Get(middleware.RequestIDHeader)
+
+	records := logRecords(t, &out)
+	assert.Len(t, records, 2)
+
+	assert.Equal(t, "internal error", records[0]["msg"])
+	assert.Equal(t, "connection reset", records[0]["error"])
+	assert.Equal(t, requestID, records[0]["request_id"])
+
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, requestID, records[1]["request_id"])
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-management/api/middleware"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutSetsRequestDeadline(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	}
+
+	started := time.Now()
+	middleware.Timeout(time.Minute)(http.HandlerFunc(handler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, started.Add(time.Minute), deadline, time.Second)
+
+	middleware.Timeout(0)(http.HandlerFunc(handler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	assert.False(t, hasDeadline)
+}
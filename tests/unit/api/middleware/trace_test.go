@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/api/middleware"
+	"user-management/internal/tracing"
+	"user-management/tests/spans"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func tracedRouter(out *bytes.Buffer, handler http.HandlerFunc) http.Handler {
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Group(func(r chi.Router) {
+		r.Use(middleware.Trace)
+		r.Use(middleware.RequestLogger(slog.New(slog.NewJSONHandler(out, nil))))
+		r.Get("/users/{id}", handler)
+	})
+	return router
+}
+
+func TestTraceContinuesIncomingTrace(t *testing.T) {
+	exporter := spans.Record(t)
+
+	shutdown, err := tracing.Setup(t.Context(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(t.Context())
+
+	var out bytes.Buffer
+	var handled trace.SpanContext
+	router := tracedRouter(&out, func(w http.ResponseWriter, r *http.Request) {
+		_, span := otel.Tracer("test").Start(r.Context(), "UserRepository.GetById")
+		handled = span.SpanContext()
+		span.End()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users/"+loggedUserID, nil)
+	request.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	server := spans.Named(t, exporter, "GET /users/{id}")
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, "/users/{id}", spans.Attribute(server, "http.route"))
+	assert.Equal(t, codes.Error, server.Status.Code)
+
+	repositorySpan := spans.Named(t, exporter, "UserRepository.GetById")
+	assert.Equal(t, server.SpanContext.SpanID(), repositorySpan.Parent.SpanID())
+	assert.Equal(t, handled.SpanID(), repositorySpan.SpanContext.SpanID())
+
+	records := logRecords(t, &out)
+	assert.Equal(t, server.SpanContext.TraceID().String(), records[0]["trace_id"])
+	assert.Equal(t, server.SpanContext.SpanID().String(), records[0]["span_id"])
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUserEvents(t *testing.T) {
+	before := domain.User{UserId: uuid.New(), FirstName: "Ada", Status: domain.UserStatusActive, Version: 1}
+
+	renamed := before
+	renamed.FirstName = "Grace"
+	renamed.Version = 2
+
+	events := domain.NewUserEvents(domain.UserUpdated, before, renamed, "")
+	assert.Len(t, events, 1)
+	assert.Equal(t, domain.UserEventUpdated, events[0].Type)
+	assert.Equal(t, 2, events[0].Version)
+	assert.Equal(t, domain.UserChanges{"firstName": {From: "Ada", To: "Grace"}}, events[0].Changes)
+
+	suspended := before
+	suspended.Status = domain.UserStatusSuspended
+
+	events = domain.NewUserEvents(domain.UserUpdated, before, suspended, "chargeback")
+	assert.Len(t, events, 1)
+	assert.Equal(t, domain.UserEventStatusChanged, events[0].Type)
+	assert.Equal(t, "chargeback", events[0].Reason)
+
+	renamed.Status = domain.UserStatusDeactivated
+	events = domain.NewUserEvents(domain.UserUpdated, before, renamed, "")
+	assert.Len(t, events, 2)
+	assert.Equal(t, domain.UserEventUpdated, events[0].Type)
+	assert.NotContains(t, events[0].Changes, "status")
+	assert.Equal(t, domain.UserEventStatusChanged, events[1].Type)
+	assert.NotEqual(t, events[0].ID, events[1].ID)
+
+	events = domain.NewUserEvents(domain.UserCreated, domain.User{}, before, "")
+	assert.Equal(t, domain.UserEventCreated, events[0].Type)
+	assert.Equal(t, "Ada", events[0].Changes["firstName"].To)
+
+	events = domain.NewUserEvents(domain.UserDeleted, before, before, "")
+	assert.Equal(t, domain.UserEventDeleted, events[0].Type)
+	assert.Empty(t, events[0].Changes)
+}
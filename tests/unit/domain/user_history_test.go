@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	"user-management/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffUsers(t *testing.T) {
+	before := domain.User{
+		FirstName:   "Ada",
+		LastName:    "Lovelace",
+		Email:       "ada@example.com",
+		Phone:       "+14155552671",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+	}
+	after := before
+	after.Email = "countess@example.com"
+	after.Status = domain.UserStatusSuspended
+
+	assert.Equal(t, domain.UserChanges{
+		"email":  {From: "ada@example.com", To: "countess@example.com"},
+		"status": {From: "active", To: "suspended"},
+	}, domain.DiffUsers(before, after))
+
+	created := domain.DiffUsers(domain.User{}, before)
+	assert.Len(t, created, 6)
+	assert.Equal(t, domain.FieldChange{To: "1990-04-21"}, created["dateOfBirth"])
+}
+
+func TestReplayUser(t *testing.T) {
+	id := uuid.New()
+	createdAt := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+
+	revisions := []domain.UserRevision{
+		{UserId: id, Revision: 1, Operation: domain.UserCreated, ChangedBy: "alice", ChangedAt: createdAt, Changes: domain.UserChanges{
+			"firstName":   {To: "Ada"},
+			"dateOfBirth": {To: "1990-04-21"},
+			"status":      {To: "active"},
+		}},
+		{UserId: id, Revision: 2, Operation: domain.UserUpdated, ChangedBy: "bob", ChangedAt: updatedAt, Changes: domain.UserChanges{
+			"firstName": {From: "Ada", To: "Grace"},
+		}},
+	}
+
+	user, deleted := domain.ReplayUser(revisions)
+	assert.False(t, deleted)
+	assert.Equal(t, id, user.UserId)
+	assert.Equal(t, "Grace", user.FirstName)
+	assert.Equal(t, time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC), user.DateOfBirth)
+	assert.Equal(t, domain.UserStatusActive, user.Status)
+	assert.Equal(t, 2, user.Version)
+	assert.Equal(t, "alice", user.CreatedBy)
+	assert.Equal(t, createdAt, user.CreatedAt)
+	assert.Equal(t, "bob", user.UpdatedBy)
+	assert.Equal(t, updatedAt, user.UpdatedAt)
+
+	revisions = append(revisions, domain.UserRevision{UserId: id, Revision: 3, Operation: domain.UserDeleted})
+	_, deleted = domain.ReplayUser(revisions)
+	assert.True(t, deleted)
+
+	revisions = append(revisions, domain.UserRevision{UserId: id, Revision: 4, Operation: domain.UserRestored})
+	_, deleted = domain.ReplayUser(revisions)
+	assert.False(t, deleted)
+}
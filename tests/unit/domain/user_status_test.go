@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"user-management/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatusTransitions(t *testing.T) {
+	allowed := []struct{ from, to domain.UserStatus }{
+		{domain.UserStatusPending, domain.UserStatusActive},
+		{domain.UserStatusActive, domain.UserStatusSuspended},
+		{domain.UserStatusActive, domain.UserStatusLocked},
+		{domain.UserStatusSuspended, domain.UserStatusActive},
+		{domain.UserStatusLocked, domain.UserStatusDeactivated},
+		{domain.UserStatusDeactivated, domain.UserStatusActive},
+		{domain.UserStatusSuspended, domain.UserStatusSuspended},
+	}
+	for _, tc := range allowed {
+		assert.NoError(t, tc.from.CheckTransition(tc.to), "%s -> %s", tc.from, tc.to)
+	}
+
+	forbidden := []struct{ from, to domain.UserStatus }{
+		{domain.UserStatusPending, domain.UserStatusSuspended},
+		{domain.UserStatusLocked, domain.UserStatusSuspended},
+		{domain.UserStatusDeactivated, domain.UserStatusSuspended},
+		{domain.UserStatusActive, domain.UserStatusPending},
+	}
+	for _, tc := range forbidden {
+		err := tc.from.CheckTransition(tc.to)
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err), "%s -> %s", tc.from, tc.to)
+	}
+}
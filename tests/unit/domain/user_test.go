@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"testing"
+	"time"
+	"user-management/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgeOn(t *testing.T) {
+	dateOfBirth := time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC)
+
+	cases := map[time.Time]int{
+		time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC): 0,
+		time.Date(2001, time.February, 28, 0, 0, 0, 0, time.UTC): 0,
+		time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC):     1,
+		time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC): 24,
+		time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC):  24,
+	}
+
+	for day, expected := range cases {
+		assert.Equal(t, expected, domain.AgeOn(dateOfBirth, day), day.Format(time.DateOnly))
+	}
+}
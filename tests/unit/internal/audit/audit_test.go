@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-management/internal/audit"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	entries []audit.Entry
+	key     []byte
+}
+
+func (m *memoryStore) Append(c context.Context, entry audit.Entry) (audit.Entry, error) {
+	entry.ID = int64(len(m.entries) + 1)
+	entry.PrevHash = audit.GenesisHash
+	if len(m.entries) > 0 {
+		entry.PrevHash = m.entries[len(m.entries)-1].Hash
+	}
+	entry.Hash = entry.ComputeHash(m.key)
+
+	m.entries = append(m.entries, entry)
+
+	return entry, nil
+}
+
+func (m *memoryStore) List(c context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	var entries []audit.Entry
+	for _, entry := range m.entries {
+		if entry.ID > filter.AfterID && len(entries) < filter.Limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+var testKey = []byte("audit-test-key")
+
+func newChain(t *testing.T, length int) *memoryStore {
+	store := &memoryStore{key: testKey}
+	occurredAt := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < length; i++ {
+		_, err := store.Append(context.Background(), audit.Entry{
+			OccurredAt: occurredAt.Add(time.Duration(i) * time.Second),
+			Actor:      "support",
+			SourceIP:   "10.0.0.1",
+			Method:     "PUT",
+			Route:      "/users/{id}",
+			TargetUser: uuid.New(),
+			Status:     200,
+			Outcome:    audit.OutcomeSuccess,
+		})
+		assert.NoError(t, err)
+	}
+
+	return store
+}
+
+func TestVerifyIntactChain(t *testing.T) {
+	checked, err := audit.Verify(context.Background(), newChain(t, 2500), testKey)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2500, checked)
+}
+
+func TestVerifyDetectsEditedEntry(t *testing.T) {
+	store := newChain(t, 5)
+	store.entries[2].Actor = "someone-else"
+
+	checked, err := audit.Verify(context.Background(), store, testKey)
+
+	var verifyErr *audit.VerifyError
+	assert.True(t, errors.As(err, &verifyErr))
+	assert.Equal(t, int64(3), verifyErr.ID)
+	assert.Equal(t, 2, checked)
+}
+
+func TestVerifyDetectsRemovedEntry(t *testing.T) {
+	store := newChain(t, 5)
+	store.entries = append(store.entries[:1], store.entries[2:]...)
+
+	_, err := audit.Verify(context.Background(), store, testKey)
+
+	var verifyErr *audit.VerifyError
+	assert.True(t, errors.As(err, &verifyErr))
+	assert.Equal(t, int64(3), verifyErr.ID)
+}
+
+func TestComputeHashIgnoresSubMicrosecondTime(t *testing.T) {
+	entry := audit.Entry{OccurredAt: time.Date(2026, time.October, 1, 12, 0, 0, 1_000, time.UTC)}
+	truncated := entry
+	entry.OccurredAt = entry.OccurredAt.Add(999 * time.Nanosecond)
+
+	assert.Equal(t, truncated.ComputeHash(testKey), entry.ComputeHash(testKey))
+}
+
+func TestVerifyDetectsChainRewrittenWithoutKey(t *testing.T) {
+	store := newChain(t, 5)
+
+	// Edit an entry and recompute every hash from there on, as someone with
+	// write access to the table but not the key could.
+	store.entries[2].Actor = "someone-else"
+	for i := 2; i < len(store.entries); i++ {
+		store.entries[i].PrevHash = store.entries[i-1].Hash
+		store.entries[i].Hash = store.entries[i].ComputeHash(nil)
+	}
+
+	_, err := audit.Verify(context.Background(), store, testKey)
+
+	var verifyErr *audit.VerifyError
+	assert.True(t, errors.As(err, &verifyErr))
+	assert.Equal(t, int64(3), verifyErr.ID)
+}
+
+func TestVerifyWithoutKey(t *testing.T) {
+	store := newChain(t, 0)
+	store.key = nil
+	for i := 0; i < 3; i++ {
+		_, err := store.Append(context.Background(), audit.Entry{Actor: "support", Status: 200, Outcome: audit.OutcomeSuccess})
+		assert.NoError(t, err)
+	}
+
+	checked, err := audit.Verify(context.Background(), store, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, checked)
+
+	_, err = audit.Verify(context.Background(), store, testKey)
+	assert.Error(t, err)
+}
+
+func TestOutcomeOf(t *testing.T) {
+	assert.Equal(t, audit.OutcomeSuccess, audit.OutcomeOf(204))
+	assert.Equal(t, audit.OutcomeSuccess, audit.OutcomeOf(304))
+	assert.Equal(t, audit.OutcomeFailure, audit.OutcomeOf(412))
+	assert.Equal(t, audit.OutcomeFailure, audit.OutcomeOf(503))
+}
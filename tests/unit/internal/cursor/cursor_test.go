@@ -0,0 +1,50 @@
+package cursor
+
+import (
+	"strings"
+	"testing"
+	"user-management/domain"
+	"user-management/internal/cursor"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	codec := cursor.NewCodec([]byte("secret"))
+
+	original := domain.UserCursor{
+		SortBy:     domain.UserSortByLastName,
+		SortDesc:   true,
+		LastValue:  "O'Brien",
+		LastUserId: uuid.New(),
+	}
+
+	decoded, err := codec.Decode(codec.Encode(original))
+
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
+
+func TestDecodeRejectsTamperedToken(t *testing.T) {
+	codec := cursor.NewCodec([]byte("secret"))
+
+	token := codec.Encode(domain.UserCursor{
+		SortBy:     domain.UserSortByAge,
+		LastValue:  "30",
+		LastUserId: uuid.New(),
+	})
+
+	forged := cursor.NewCodec([]byte("other")).Encode(domain.UserCursor{
+		SortBy:     domain.UserSortByAge,
+		LastValue:  "99",
+		LastUserId: uuid.New(),
+	})
+	forgedBody, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	for _, candidate := range []string{"", "abc", token + "x", forgedBody + "." + signature, forged} {
+		_, err := codec.Decode(candidate)
+		assert.ErrorIs(t, err, cursor.ErrInvalidToken, candidate)
+	}
+}
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"user-management/internal/logging"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWritesConfiguredFormatAndLevel(t *testing.T) {
+	var out bytes.Buffer
+
+	logger, err := logging.New(&out, "json", "warn")
+	assert.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept", "user_id", "42")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 1)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "kept", record["msg"])
+	assert.Equal(t, "42", record["user_id"])
+
+	out.Reset()
+
+	logger, err = logging.New(&out, "TEXT", "debug")
+	assert.NoError(t, err)
+
+	logger.Debug("kept", "user_id", "42")
+	assert.Contains(t, out.String(), "msg=kept user_id=42")
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	_, err := logging.New(&bytes.Buffer{}, "xml", "info")
+	assert.ErrorContains(t, err, "log format")
+
+	_, err = logging.New(&bytes.Buffer{}, "json", "loud")
+	assert.ErrorContains(t, err, "log level")
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, slog.Default(), logging.FromContext(context.Background()))
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	ctx := logging.WithLogger(context.Background(), logger)
+	assert.Same(t, logger, logging.FromContext(ctx))
+}
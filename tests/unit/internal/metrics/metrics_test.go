@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	observed := metrics.New()
+
+	observed.HTTP.Observe("GET", "/users/{id}", 200, 30*time.Millisecond)
+	observed.HTTP.Observe("GET", "/users/{id}", 200, 70*time.Millisecond)
+	observed.HTTP.Observe("GET", "/users/{id}", 404, time.Millisecond)
+
+	expected := `
+# HELP user_management_http_requests_total Requests handled, by method, route pattern and status code.
+# TYPE user_management_http_requests_total counter
+user_management_http_requests_total{method="GET",route="/users/{id}",status="200"} 2
+user_management_http_requests_total{method="GET",route="/users/{id}",status="404"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(observed.Registry, strings.NewReader(expected), "user_management_http_requests_total"))
+
+	count, err := testutil.GatherAndCount(observed.Registry, "user_management_http_request_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestRepositoryMetrics(t *testing.T) {
+	observed := metrics.New()
+
+	observed.Repository.Observe("GetById", time.Millisecond, nil)
+	observed.Repository.Observe("GetById", time.Millisecond, domain.NewNotFoundError("user not found", nil))
+	observed.Repository.Observe("Create", time.Millisecond, domain.NewConflictError("email taken", nil))
+	observed.Repository.Observe("Create", time.Millisecond, assert.AnError)
+
+	expected := `
+# HELP user_management_repository_operation_errors_total User repository calls that returned an error, by operation and error kind.
+# TYPE user_management_repository_operation_errors_total counter
+user_management_repository_operation_errors_total{kind="conflict",operation="Create"} 1
+user_management_repository_operation_errors_total{kind="internal",operation="Create"} 1
+user_management_repository_operation_errors_total{kind="not_found",operation="GetById"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(observed.Registry, strings.NewReader(expected), "user_management_repository_operation_errors_total"))
+
+	count, err := testutil.GatherAndCount(observed.Registry, "user_management_repository_operation_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMetricsFollowNamingConventions(t *testing.T) {
+	observed := metrics.New()
+	observed.HTTP.Observe("GET", "/users", 200, time.Millisecond)
+	observed.Repository.Observe("GetAll", time.Millisecond, assert.AnError)
+
+	problems, err := testutil.GatherAndLint(observed.Registry)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
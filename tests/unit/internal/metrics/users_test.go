@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type userCounts struct {
+	counts map[domain.UserStatus]int64
+	err    error
+}
+
+func (uc userCounts) CountByStatus(c context.Context) (map[domain.UserStatus]int64, error) {
+	return uc.counts, uc.err
+}
+
+func TestUserCollectorReportsEveryStatus(t *testing.T) {
+	collector := metrics.NewUserCollector(userCounts{counts: map[domain.UserStatus]int64{
+		domain.UserStatusActive:  12,
+		domain.UserStatusPending: 3,
+	}}, time.Second)
+
+	expected := `
+# HELP user_management_users Users that are not deleted, by status.
+# TYPE user_management_users gauge
+user_management_users{status="active"} 12
+user_management_users{status="deactivated"} 0
+user_management_users{status="locked"} 0
+user_management_users{status="pending"} 3
+user_management_users{status="suspended"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestUserCollectorLeavesOutFailedCounts(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewUserCollector(userCounts{err: assert.AnError}, time.Second))
+
+	_, err := registry.Gather()
+	assert.ErrorIs(t, err, assert.AnError)
+}
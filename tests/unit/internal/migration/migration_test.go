@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"testing"
+	"user-management/internal/migration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailableListsEmbeddedMigrationsInOrder(t *testing.T) {
+	available, err := migration.Available()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, available)
+
+	assert.Equal(t, migration.Migration{Version: 1, Name: "create_users"}, available[0])
+	for i, m := range available {
+		assert.Equal(t, uint(i+1), m.Version, "migrations should be numbered without gaps")
+	}
+}
+
+func TestStatusCheck(t *testing.T) {
+	cases := []struct {
+		name   string
+		status migration.Status
+		ok     bool
+	}{
+		{name: "Current", status: migration.Status{Version: 12, Latest: 12}, ok: true},
+		{name: "Behind", status: migration.Status{Version: 3, Latest: 12}, ok: true},
+		{name: "Empty", status: migration.Status{Latest: 12}, ok: true},
+		{name: "Dirty", status: migration.Status{Version: 12, Dirty: true, Latest: 12}},
+		{name: "Newer", status: migration.Status{Version: 13, Latest: 12}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.status.Check()
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
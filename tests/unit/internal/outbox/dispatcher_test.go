@@ -0,0 +1,179 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"user-management/internal/outbox"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore mimics the outbox table: messages stay pending until they are
+// delivered or parked.
+type memoryStore struct {
+	pending  []outbox.Message
+	claimed  map[int64]bool
+	parked   []outbox.Message
+	failures int
+}
+
+func newMemoryStore(count int) *memoryStore {
+	store := &memoryStore{claimed: map[int64]bool{}}
+	for i := 1; i <= count; i++ {
+		store.pending = append(store.pending, outbox.Message{ID: int64(i), EventID: uuid.New(), Type: "user.created"})
+	}
+	return store
+}
+
+func (m *memoryStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]outbox.Message, error) {
+	var messages []outbox.Message
+	for _, message := range m.pending {
+		if len(messages) == limit {
+			break
+		}
+		if !m.claimed[message.ID] {
+			m.claimed[message.ID] = true
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (m *memoryStore) MarkDelivered(ctx context.Context, id int64) error {
+	m.remove(id)
+	return nil
+}
+
+func (m *memoryStore) RecordFailure(ctx context.Context, id int64, lastError string, park bool) error {
+	m.failures++
+	for i := range m.pending {
+		if m.pending[i].ID == id {
+			m.pending[i].Attempts++
+			if park {
+				m.parked = append(m.parked, m.pending[i])
+				m.remove(id)
+			}
+			break
+		}
+	}
+	delete(m.claimed, id)
+	return nil
+}
+
+func (m *memoryStore) Release(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		delete(m.claimed, id)
+	}
+	return nil
+}
+
+// DeleteDelivered has nothing to delete: delivered messages are dropped as
+// soon as they are marked.
+func (m *memoryStore) DeleteDelivered(ctx context.Context, retention time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryStore) remove(id int64) {
+	m.pending = slices.DeleteFunc(m.pending, func(message outbox.Message) bool { return message.ID == id })
+	delete(m.claimed, id)
+}
+
+// failingPublisher rejects the messages with the given IDs.
+type failingPublisher struct {
+	outbox.MemoryPublisher
+	failing map[int64]bool
+}
+
+func (f *failingPublisher) Publish(ctx context.Context, message outbox.Message) error {
+	if f.failing[message.ID] {
+		return errors.New("rejected")
+	}
+	return f.MemoryPublisher.Publish(ctx, message)
+}
+
+func TestDispatchOncePublishesInOrder(t *testing.T) {
+	store := newMemoryStore(3)
+	publisher := &outbox.MemoryPublisher{}
+	dispatcher := &outbox.Dispatcher{Store: store, Publisher: publisher, BatchSize: 2}
+
+	delivered, err := dispatcher.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+
+	delivered, err = dispatcher.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+
+	messages := publisher.Messages()
+	assert.Len(t, messages, 3)
+	for i, message := range messages {
+		assert.Equal(t, int64(i+1), message.ID)
+	}
+}
+
+func TestDispatchOnceKeepsFailedMessages(t *testing.T) {
+	store := newMemoryStore(2)
+	publisher := &outbox.MemoryPublisher{Err: errors.New("broker unavailable")}
+	dispatcher := &outbox.Dispatcher{Store: store, Publisher: publisher, BatchSize: 10}
+
+	delivered, err := dispatcher.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Len(t, store.pending, 2)
+	assert.Equal(t, 1, store.pending[0].Attempts)
+
+	publisher.Err = nil
+
+	delivered, _ = dispatcher.DispatchOnce(context.Background())
+	assert.Equal(t, 2, delivered)
+	assert.Empty(t, store.pending)
+	assert.Empty(t, store.claimed)
+}
+
+func TestDispatchOnceParksMessageOutOfAttempts(t *testing.T) {
+	store := newMemoryStore(3)
+	publisher := &failingPublisher{failing: map[int64]bool{1: true}}
+	dispatcher := &outbox.Dispatcher{Store: store, Publisher: publisher, BatchSize: 10, MaxAttempts: 2}
+
+	delivered, err := dispatcher.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Empty(t, store.parked)
+	assert.Empty(t, store.claimed)
+
+	delivered, err = dispatcher.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	assert.Len(t, store.parked, 1)
+	assert.Equal(t, int64(1), store.parked[0].ID)
+	assert.Equal(t, 2, store.parked[0].Attempts)
+	assert.Empty(t, store.pending)
+
+	messages := publisher.Messages()
+	assert.Len(t, messages, 2)
+	assert.Equal(t, int64(2), messages[0].ID)
+}
+
+func TestRunDrainsBacklogAndStops(t *testing.T) {
+	store := newMemoryStore(25)
+	publisher := &outbox.MemoryPublisher{}
+	dispatcher := &outbox.Dispatcher{Store: store, Publisher: publisher, BatchSize: 10, Interval: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+
+	// Full batches are followed straight away, so the backlog drains well
+	// before the hourly poll.
+	assert.Eventually(t, func() bool { return len(publisher.Messages()) == 25 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
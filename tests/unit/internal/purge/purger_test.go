@@ -0,0 +1,92 @@
+package purge
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/outbox"
+	"user-management/internal/purge"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type purgeRecorder struct {
+	domain.UserRepository
+	before time.Time
+}
+
+func (p *purgeRecorder) PurgeDeleted(c context.Context, before time.Time) (int64, error) {
+	p.before = before
+	return 1, nil
+}
+
+type expiredKeys struct {
+	domain.IdempotencyRepository
+	calls int
+}
+
+func (e *expiredKeys) DeleteExpired(c context.Context) (int64, error) {
+	e.calls++
+	return 0, nil
+}
+
+type deliveredMessages struct {
+	outbox.Store
+	retentions []time.Duration
+}
+
+func (d *deliveredMessages) DeleteDelivered(c context.Context, retention time.Duration) (int64, error) {
+	d.retentions = append(d.retentions, retention)
+	return 3, nil
+}
+
+func TestPurgeOnceUsesRetention(t *testing.T) {
+	users := &purgeRecorder{}
+	keys := &expiredKeys{}
+	messages := &deliveredMessages{}
+	purger := &purge.Purger{
+		Users:           users,
+		IdempotencyKeys: keys,
+		Outbox:          messages,
+		Retention:       48 * time.Hour,
+		OutboxRetention: 7 * 24 * time.Hour,
+	}
+
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	purger.PurgeOnce(context.Background(), now)
+
+	assert.Equal(t, now.Add(-48*time.Hour), users.before)
+	assert.Equal(t, 1, keys.calls)
+	assert.Equal(t, []time.Duration{7 * 24 * time.Hour}, messages.retentions)
+}
+
+func TestPurgeOnceKeepsDeliveredMessagesWithoutRetention(t *testing.T) {
+	messages := &deliveredMessages{}
+	purger := &purge.Purger{Users: &purgeRecorder{}, Outbox: messages, Retention: time.Hour}
+
+	purger.PurgeOnce(context.Background(), time.Now())
+
+	assert.Empty(t, messages.retentions)
+}
+
+func TestRunStopsWithContext(t *testing.T) {
+	users := &purgeRecorder{}
+	purger := &purge.Purger{Users: users, Retention: time.Hour, Interval: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		purger.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after its context was cancelled")
+	}
+	assert.False(t, users.before.IsZero())
+}
@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+	"user-management/internal/stream"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func event(id int) stream.Event {
+	return stream.Event{ID: fmt.Sprint(id), Type: "user.updated"}
+}
+
+func ids(events []stream.Event) []string {
+	result := make([]string, 0, len(events))
+	for _, e := range events {
+		result = append(result, e.ID)
+	}
+	return result
+}
+
+func TestSubscribeReceivesLaterEvents(t *testing.T) {
+	broker := stream.NewBroker(10)
+	broker.Publish(event(1))
+
+	subscription, backlog, resumed := broker.Subscribe("")
+	defer subscription.Close()
+
+	assert.True(t, resumed)
+	assert.Empty(t, backlog)
+
+	broker.Publish(event(2))
+	assert.Equal(t, "2", (<-subscription.Events()).ID)
+}
+
+func TestSubscribeResumesAfterLastEvent(t *testing.T) {
+	broker := stream.NewBroker(10)
+	for i := 1; i <= 4; i++ {
+		broker.Publish(event(i))
+	}
+
+	subscription, backlog, resumed := broker.Subscribe("2")
+	defer subscription.Close()
+
+	assert.True(t, resumed)
+	assert.Equal(t, []string{"3", "4"}, ids(backlog))
+}
+
+func TestSubscribeAfterForgottenEventReplaysWhatIsLeft(t *testing.T) {
+	broker := stream.NewBroker(2)
+	for i := 1; i <= 4; i++ {
+		broker.Publish(event(i))
+	}
+
+	subscription, backlog, resumed := broker.Subscribe("1")
+	defer subscription.Close()
+
+	assert.False(t, resumed)
+	assert.Equal(t, []string{"3", "4"}, ids(backlog))
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	broker := stream.NewBroker(0)
+
+	slow, _, _ := broker.Subscribe("")
+	fast, _, _ := broker.Subscribe("")
+	defer fast.Close()
+
+	received := 0
+	for i := 0; i < 100; i++ {
+		broker.Publish(event(i))
+		<-fast.Events()
+		received++
+	}
+
+	count := 0
+	for range slow.Events() {
+		count++
+	}
+
+	assert.Equal(t, 100, received)
+	assert.Less(t, count, 100)
+}
+
+func TestResetForgetsEventsAndDropsSubscribers(t *testing.T) {
+	broker := stream.NewBroker(10)
+	broker.Publish(event(1))
+
+	subscription, _, _ := broker.Subscribe("")
+	broker.Reset()
+
+	_, open := <-subscription.Events()
+	assert.False(t, open)
+
+	resubscription, backlog, resumed := broker.Subscribe("1")
+	defer resubscription.Close()
+
+	assert.False(t, resumed)
+	assert.Empty(t, backlog)
+}
+
+func TestCloseEndsCurrentAndNewSubscriptions(t *testing.T) {
+	broker := stream.NewBroker(10)
+
+	subscription, _, _ := broker.Subscribe("")
+	broker.Close()
+
+	_, open := <-subscription.Events()
+	assert.False(t, open)
+
+	late, _, _ := broker.Subscribe("")
+	_, open = <-late.Events()
+	assert.False(t, open)
+
+	// Closing a dropped subscription is harmless.
+	subscription.Close()
+}
@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-management/internal/tracing"
+	"user-management/tests/spans"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const getUser = "-- name: GetUser :one\nSELECT user_id FROM users WHERE user_id = $1"
+
+func runQuery(ctx context.Context, sql string, err error) {
+	tracer := tracing.QueryTracer{}
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{"secret@example.com"}})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: err})
+}
+
+func TestQueryTracerRecordsStatementsUnderParent(t *testing.T) {
+	exporter := spans.Record(t)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "UserRepository.GetById")
+	runQuery(ctx, getUser, nil)
+	runQuery(ctx, "\n  update users SET status = 'active'", nil)
+	parent.End()
+
+	query := spans.Named(t, exporter, "GetUser")
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	assert.Equal(t, "postgresql", spans.Attribute(query, "db.system.name"))
+	assert.Equal(t, "GetUser", spans.Attribute(query, "db.operation.name"))
+	assert.Equal(t, getUser, spans.Attribute(query, "db.query.text"))
+	assert.Equal(t, int64(1), spans.Attribute(query, "db.response.affected_rows"))
+	assert.Equal(t, codes.Unset, query.Status.Code)
+
+	spans.Named(t, exporter, "UPDATE")
+
+	for _, span := range exporter.GetSpans() {
+		for _, attribute := range span.Attributes {
+			assert.NotContains(t, attribute.Value.Emit(), "secret@example.com")
+		}
+	}
+}
+
+func TestQueryTracerRecordsFailures(t *testing.T) {
+	exporter := spans.Record(t)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	runQuery(ctx, getUser, pgx.ErrNoRows)
+	runQuery(ctx, "-- name: CreateUser :one\nINSERT INTO users DEFAULT VALUES", errors.New("connection reset"))
+	parent.End()
+
+	assert.Equal(t, codes.Unset, spans.Named(t, exporter, "GetUser").Status.Code)
+
+	failed := spans.Named(t, exporter, "CreateUser")
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	assert.Len(t, failed.Events, 1)
+}
+
+func TestQueryTracerSkipsUntracedStatements(t *testing.T) {
+	exporter := spans.Record(t)
+
+	runQuery(context.Background(), getUser, nil)
+
+	assert.Empty(t, exporter.GetSpans())
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"user-management/internal/tracing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestSetupInstallsTraceContextPropagation(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	incoming := http.Header{}
+	incoming.Set("Traceparent", traceparent)
+
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(incoming))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", trace.SpanContextFromContext(ctx).TraceID().String())
+
+	outgoing := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(outgoing))
+	assert.Equal(t, traceparent, outgoing.Get("Traceparent"))
+}
+
+func TestSetupExporters(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	for _, exporter := range []string{tracing.ExporterStdout, tracing.ExporterOTLP} {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+			Exporter:     exporter,
+			OTLPEndpoint: "http://localhost:4318",
+			ServiceName:  "user-management",
+			SampleRatio:  1,
+		})
+		require.NoError(t, err, exporter)
+		assert.NoError(t, shutdown(context.Background()), exporter)
+	}
+
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "zipkin"})
+	assert.ErrorContains(t, err, "invalid tracing exporter")
+}
@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"testing"
+	"time"
+	"user-management/internal/validator"
+
+	govalidator "github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslatorFor(t *testing.T) {
+	validator.Init()
+
+	cases := map[string]string{
+		"":                         "en",
+		"es":                       "es",
+		"es-AR":                    "es",
+		"de-DE, es;q=0.5":          "es",
+		"en;q=0.4, es;q=0.9":       "es",
+		"ja":                       "en",
+		"this is not a header ;;;": "en",
+	}
+
+	for header, expected := range cases {
+		assert.Equal(t, expected, validator.TranslatorFor(header).Locale(), header)
+	}
+}
+
+func TestDateOfBirthRules(t *testing.T) {
+	validator.Init()
+
+	type person struct {
+		DateOfBirth string `json:"dateOfBirth" validate:"notfuture,minage=18"`
+	}
+
+	today := time.Now()
+
+	cases := map[string]bool{
+		today.AddDate(-18, 0, 0).Format(time.DateOnly): true,
+		today.AddDate(-18, 0, 1).Format(time.DateOnly): false,
+		today.AddDate(0, 0, 1).Format(time.DateOnly):   false,
+		"not a date": false,
+	}
+
+	for dateOfBirth, valid := range cases {
+		err := validator.Validate.Struct(person{DateOfBirth: dateOfBirth})
+		assert.Equal(t, valid, err == nil, dateOfBirth)
+	}
+}
+
+func TestDateOfBirthMessagesAreTranslated(t *testing.T) {
+	validator.Init()
+
+	type person struct {
+		DateOfBirth string `json:"dateOfBirth" validate:"minage=13"`
+	}
+
+	err := validator.Validate.Struct(person{DateOfBirth: time.Now().Format(time.DateOnly)})
+	fieldErr := err.(govalidator.ValidationErrors)[0]
+
+	assert.Equal(t, "dateOfBirth must make the user at least 13 years old", fieldErr.Translate(validator.TranslatorFor("en")))
+	assert.Equal(t, "dateOfBirth debe indicar una edad de al menos 13 años", fieldErr.Translate(validator.TranslatorFor("es")))
+}
@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"user-management/internal/outbox"
+	"user-management/internal/tracing"
+	"user-management/internal/webhook"
+	"user-management/tests/spans"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// memoryStore hands out its deliveries once and keeps what was recorded.
+type memoryStore struct {
+	webhook.Store
+	mu       sync.Mutex
+	due      []webhook.Delivery
+	recorded []webhook.Delivery
+	attempts []webhook.Attempt
+	enqueued []uuid.UUID
+}
+
+func (m *memoryStore) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	claimed := m.due
+	m.due = nil
+	return claimed, nil
+}
+
+func (m *memoryStore) RecordAttempt(ctx context.Context, delivery webhook.Delivery, attempt webhook.Attempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recorded = append(m.recorded, delivery)
+	m.attempts = append(m.attempts, attempt)
+	return nil
+}
+
+func (m *memoryStore) Enqueue(ctx context.Context, eventID uuid.UUID, eventType string, payload []byte) error {
+	m.enqueued = append(m.enqueued, eventID)
+	return nil
+}
+
+func newDelivery(url string, attempts int) webhook.Delivery {
+	return webhook.Delivery{
+		ID:        1,
+		EventID:   uuid.New(),
+		EventType: "user.created",
+		Payload:   []byte(`{"type":"user.created"}`),
+		Status:    webhook.DeliveryPending,
+		Attempts:  attempts,
+		URL:       url,
+		Secret:    "0123456789abcdef",
+	}
+}
+
+func newDeliverer(store webhook.Store) *webhook.Deliverer {
+	return &webhook.Deliverer{
+		Store:       store,
+		BatchSize:   10,
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+		RetryDelay:  time.Minute,
+	}
+}
+
+func TestDeliverSignsAndMarksDelivered(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer receiver.Close()
+
+	delivery := newDelivery(receiver.URL, 0)
+	store := &memoryStore{due: []webhook.Delivery{delivery}}
+
+	attempted, err := newDeliverer(store).DeliverOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+
+	assert.Equal(t, delivery.Payload, body)
+	assert.Equal(t, delivery.EventID.String(), received.Header.Get(webhook.IDHeader))
+	assert.Equal(t, "user.created", received.Header.Get(webhook.EventHeader))
+	timestamp := received.Header.Get(webhook.TimestampHeader)
+	assert.Equal(t, webhook.Sign(delivery.Secret, timestamp, body), received.Header.Get(webhook.SignatureHeader))
+
+	assert.Equal(t, webhook.DeliveryDelivered, store.recorded[0].Status)
+	assert.Equal(t, 1, store.recorded[0].Attempts)
+	assert.False(t, store.recorded[0].DeliveredAt.IsZero())
+	assert.Equal(t, http.StatusOK, store.attempts[0].StatusCode)
+}
+
+func TestDeliverRetriesThenDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	store := &memoryStore{due: []webhook.Delivery{newDelivery(receiver.URL, 1)}}
+	before := time.Now()
+
+	_, err := newDeliverer(store).DeliverOnce(context.Background())
+	assert.NoError(t, err)
+
+	retried := store.recorded[0]
+	assert.Equal(t, webhook.DeliveryPending, retried.Status)
+	assert.Equal(t, 2, retried.Attempts)
+	assert.Equal(t, "subscriber responded 503", retried.LastError)
+	assert.WithinDuration(t, before.Add(2*time.Minute), retried.NextAttemptAt, 5*time.Second)
+
+	store.due = []webhook.Delivery{retried}
+
+	_, err = newDeliverer(store).DeliverOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, webhook.DeliveryDead, store.recorded[1].Status)
+	assert.Equal(t, 3, store.recorded[1].Attempts)
+}
+
+func TestDeliverRecordsUnreachableReceiver(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	receiver.Close()
+
+	store := &memoryStore{due: []webhook.Delivery{newDelivery(receiver.URL, 0)}}
+
+	_, err := newDeliverer(store).DeliverOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, store.attempts[0].StatusCode)
+	assert.NotEmpty(t, store.attempts[0].Error)
+	assert.Equal(t, webhook.DeliveryPending, store.recorded[0].Status)
+}
+
+func TestDeliverPropagatesTrace(t *testing.T) {
+	exporter := spans.Record(t)
+
+	shutdown, err := tracing.Setup(t.Context(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+	defer shutdown(t.Context())
+
+	var traceparent string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("Traceparent")
+	}))
+	defer receiver.Close()
+
+	delivery := newDelivery(receiver.URL, 0)
+	store := &memoryStore{due: []webhook.Delivery{delivery}}
+
+	deliverer := newDeliverer(store)
+	deliverer.Client = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+	_, err = deliverer.DeliverOnce(context.Background())
+	require.NoError(t, err)
+
+	deliver := spans.Named(t, exporter, "webhook.deliver")
+	assert.Equal(t, delivery.EventID.String(), spans.Attribute(deliver, "webhook.event.id"))
+
+	client := spans.Named(t, exporter, "HTTP POST")
+	assert.Equal(t, deliver.SpanContext.SpanID(), client.Parent.SpanID())
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", client.SpanContext.TraceID(), client.SpanContext.SpanID()), traceparent)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhook.RetryDelay(30*time.Second, 1))
+	assert.Equal(t, 4*time.Minute, webhook.RetryDelay(30*time.Second, 4))
+	assert.Equal(t, 6*time.Hour, webhook.RetryDelay(30*time.Second, 40))
+}
+
+func TestFanoutEnqueuesOutboxMessages(t *testing.T) {
+	store := &memoryStore{}
+	eventID := uuid.New()
+
+	err := webhook.Fanout{Store: store}.Publish(context.Background(), outbox.Message{EventID: eventID, Type: "user.deleted"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{eventID}, store.enqueued)
+}
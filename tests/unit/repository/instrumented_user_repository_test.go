@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/internal/metrics"
+	"user-management/repository"
+	"user-management/tests/conformance"
+	"user-management/tests/spans"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestInstrumentedUserRepository(t *testing.T) {
+	conformance.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+		return repository.NewInstrumentedUserRepository(repository.NewMemoryUserRepository(), nil)
+	})
+}
+
+func TestInstrumentedUserRepositoryRecordsSpans(t *testing.T) {
+	exporter := spans.Record(t)
+	userRepository := repository.NewInstrumentedUserRepository(repository.NewMemoryUserRepository(), nil)
+
+	user := domain.User{
+		UserId:      uuid.New(),
+		FirstName:   "Traced",
+		LastName:    "Tester",
+		Email:       "traced@example.com",
+		DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+		Status:      domain.UserStatusActive,
+	}
+
+	ctx, request := otel.Tracer("test").Start(context.Background(), "POST /users")
+	_, err := userRepository.Create(ctx, &user)
+	require.NoError(t, err)
+	_, err = userRepository.GetById(ctx, uuid.New())
+	assert.Equal(t, domain.ErrorKindNotFound, domain.ErrorKindOf(err))
+	request.End()
+
+	created := spans.Named(t, exporter, "UserRepository.Create")
+	assert.Equal(t, request.SpanContext().SpanID(), created.Parent.SpanID())
+	assert.Equal(t, user.UserId.String(), spans.Attribute(created, "user.id"))
+	assert.Equal(t, codes.Unset, created.Status.Code)
+
+	// A missing user is an answer, not a failure.
+	missing := spans.Named(t, exporter, "UserRepository.GetById")
+	assert.Equal(t, request.SpanContext().SpanID(), missing.Parent.SpanID())
+	assert.Equal(t, "not_found", spans.Attribute(missing, "error.type"))
+	assert.Equal(t, codes.Unset, missing.Status.Code)
+}
+
+func TestInstrumentedUserRepositoryRecordsMetrics(t *testing.T) {
+	observed := metrics.New()
+	userRepository := repository.NewInstrumentedUserRepository(repository.NewMemoryUserRepository(), observed.Repository)
+
+	_, err := userRepository.GetById(context.Background(), uuid.New())
+	assert.Error(t, err)
+	_, err = userRepository.CountByStatus(context.Background())
+	assert.NoError(t, err)
+
+	expected := `
+# HELP user_management_repository_operation_errors_total User repository calls that returned an error, by operation and error kind.
+# TYPE user_management_repository_operation_errors_total counter
+user_management_repository_operation_errors_total{kind="not_found",operation="GetById"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(observed.Registry, strings.NewReader(expected), "user_management_repository_operation_errors_total"))
+
+	count, err := testutil.GatherAndCount(observed.Registry, "user_management_repository_operation_duration_seconds")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+// cancelledRepository fails the way a statement Postgres cancelled for a
+// departed client does.
+type cancelledRepository struct {
+	domain.UserRepository
+}
+
+func (cancelledRepository) GetById(c context.Context, id uuid.UUID) (domain.User, error) {
+	return domain.User{}, domain.NewTimeoutError("database did not respond in time", nil)
+}
+
+func TestInstrumentedUserRepositoryCountsCancellation(t *testing.T) {
+	observed := metrics.New()
+	userRepository := repository.NewInstrumentedUserRepository(cancelledRepository{}, observed.Repository)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := userRepository.GetById(ctx, uuid.New())
+	assert.Equal(t, domain.ErrorKindCanceled, domain.ErrorKindOf(err))
+
+	expected := `
+# HELP user_management_repository_operation_errors_total User repository calls that returned an error, by operation and error kind.
+# TYPE user_management_repository_operation_errors_total counter
+user_management_repository_operation_errors_total{kind="canceled",operation="GetById"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(observed.Registry, strings.NewReader(expected), "user_management_repository_operation_errors_total"))
+}
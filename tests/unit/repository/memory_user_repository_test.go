@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"user-management/domain"
+	"user-management/repository"
+	"user-management/tests/conformance"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+	conformance.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+		return repository.NewMemoryUserRepository()
+	})
+}
+
+func TestMemoryUserRepositoryEnforcesUniqueEmailConcurrently(t *testing.T) {
+	userRepository := repository.NewMemoryUserRepository()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := userRepository.Create(context.Background(), &domain.User{
+				UserId:      uuid.New(),
+				FirstName:   fmt.Sprintf("Racer%d", i),
+				LastName:    "Tester",
+				Email:       "race@example.com",
+				DateOfBirth: time.Date(1990, time.April, 21, 0, 0, 0, 0, time.UTC),
+				Status:      domain.UserStatusActive,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.Equal(t, domain.ErrorKindConflict, domain.ErrorKindOf(err))
+	}
+
+	assert.Equal(t, 1, created)
+}